@@ -0,0 +1,252 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sharik709/bootstraper/providers"
+	"github.com/sharik709/bootstraper/util"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// dryRunPlan is the --dry-run --output json/yaml document
+type dryRunPlan struct {
+	Provider string            `json:"provider"`
+	Project  string            `json:"project"`
+	Path     string            `json:"path"`
+	Options  map[string]string `json:"options"`
+	// Passthrough holds the raw generator args given after "--"
+	Passthrough []string               `json:"passthrough,omitempty"`
+	Steps       []providers.Invocation `json:"steps"`
+}
+
+// exactArgsBeforeDash accepts exactly n positional args before "--"; args
+// after it are passed through to the generator
+func exactArgsBeforeDash(n int) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		positional, _ := splitPassthrough(cmd, args)
+		return cobra.ExactArgs(n)(cmd, positional)
+	}
+}
+
+// splitPassthrough splits args into the positional args and the
+// pass-through args given after "--"
+func splitPassthrough(cmd *cobra.Command, args []string) ([]string, []string) {
+	if dash := cmd.ArgsLenAtDash(); dash >= 0 && dash <= len(args) {
+		return args[:dash], args[dash:]
+	}
+	return args, nil
+}
+
+// addBootstrapFlags registers the flags shared by commands that run a
+// provider on flags, which belongs to cmd
+func addBootstrapFlags(cmd *cobra.Command, flags *pflag.FlagSet) {
+	flags.Bool("dry-run", false, "Print the commands that would run without executing them; the version is still resolved online")
+	flags.Bool("no-defaults", false, "Ignore option defaults from the configuration")
+	addPlacementFlags(cmd, flags)
+}
+
+// addPlacementFlags registers the flags that choose where a project is created
+func addPlacementFlags(cmd *cobra.Command, flags *pflag.FlagSet) {
+	flags.String("dir", "", "Directory to create the project in (defaults to the projectDir setting)")
+	flags.Bool("here", false, "Create the project in the current directory")
+	cmd.MarkFlagsMutuallyExclusive("dir", "here")
+	cmd.RegisterFlagCompletionFunc("dir", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return nil, cobra.ShellCompDirectiveFilterDirs
+	})
+}
+
+// createProject resolves the provider options from defaults and flags and
+// bootstraps the project, passing passthrough to the generator
+func createProject(cmd *cobra.Command, provider providers.Provider, projectName string, passthrough []string) error {
+	// Merge defaults, configured defaults and flags
+	options, sources, err := resolveOptions(cmd, provider)
+	if err != nil {
+		return err
+	}
+
+	if verbose {
+		printOptionSources(cmd.ErrOrStderr(), options, sources)
+	}
+
+	// Bootstrap the project
+	return runProvider(cmd, provider, projectName, options, passthrough)
+}
+
+// validateProjectName rejects names that are not a single path element
+func validateProjectName(projectName string) error {
+	if projectName == "" || projectName == "." || projectName == ".." || filepath.Base(projectName) != projectName {
+		return fmt.Errorf("invalid project name: %q", projectName)
+	}
+	return nil
+}
+
+// resolveProject determines where the project is created: --here uses the
+// current directory, --dir the given one and otherwise the configured
+// projectDir (or the current directory when it is not set). The returned
+// directory is absolute.
+func resolveProject(cmd *cobra.Command, projectName string) (providers.Project, error) {
+	if err := validateProjectName(projectName); err != nil {
+		return providers.Project{}, err
+	}
+
+	here, _ := cmd.Flags().GetBool("here")
+	dir, _ := cmd.Flags().GetString("dir")
+
+	switch {
+	case here:
+		dir = "."
+	case dir != "":
+		dir = util.ExpandPath(dir)
+	default:
+		config, err := util.LoadConfig()
+		if err != nil {
+			return providers.Project{}, fmt.Errorf("failed to load config: %v", err)
+		}
+		dir = util.ExpandPath(config.ProjectDir)
+		if dir == "" {
+			dir = "."
+		}
+	}
+
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return providers.Project{}, fmt.Errorf("invalid project directory %s: %v", dir, err)
+	}
+
+	return providers.Project{Name: projectName, Dir: abs}, nil
+}
+
+// runProvider bootstraps the project and records it in the history log, or
+// prints its plan with --dry-run
+func runProvider(cmd *cobra.Command, provider providers.Provider, projectName string, options map[string]string, passthrough []string) error {
+	project, err := resolveProject(cmd, projectName)
+	if err != nil {
+		return err
+	}
+	project.Args = passthrough
+
+	// Pin the framework version so the run is reproducible. Dry runs do
+	// this too, so the plan shows the version that would be used, which
+	// queries the package registry.
+	requestedVersion, err := resolveVersion(cmd, provider, options)
+	if err != nil {
+		return err
+	}
+
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	if !dryRun {
+		if err := os.MkdirAll(project.Dir, 0755); err != nil {
+			return fmt.Errorf("failed to create project directory: %v", err)
+		}
+
+		err := providers.BootstrapIn(provider, project, options)
+		recordHistory(cmd, provider, project, options, err)
+		if err != nil {
+			return err
+		}
+
+		meta := util.ProjectMetadata{
+			Provider:         provider.Name(),
+			Version:          options["version"],
+			RequestedVersion: requestedVersion,
+			Options:          options,
+			CreatedAt:        time.Now().UTC(),
+			BtVersion:        Version,
+		}
+		if err := util.WriteProjectMetadata(project.Path(), meta); err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %v\n", err)
+		}
+
+		fmt.Printf("Project created at %s\n", project.Path())
+		return nil
+	}
+
+	planner, ok := provider.(providers.Planner)
+	if !ok {
+		return fmt.Errorf("%s does not support --dry-run", provider.Name())
+	}
+
+	plan, err := planner.Plan(project, options)
+	if err != nil {
+		return err
+	}
+
+	// Show the directories the steps would actually run in
+	for i := range plan {
+		plan[i].Dir = absDir(plan[i].Dir)
+	}
+
+	format, _ := outputFormat()
+	if format != outputTable {
+		return writeStructured(cmd.OutOrStdout(), format, dryRunPlan{
+			Provider:    provider.Name(),
+			Project:     projectName,
+			Path:        project.Path(),
+			Options:     options,
+			Passthrough: passthrough,
+			Steps:       plan,
+		})
+	}
+
+	printPlan(cmd.OutOrStdout(), provider.Name(), project, plan)
+	return nil
+}
+
+// printPlan writes a human-readable description of a plan
+func printPlan(w io.Writer, providerName string, project providers.Project, plan []providers.Invocation) {
+	fmt.Fprintf(w, "Dry run: %s project %s at %s (nothing will be executed)\n", providerName, project.Name, project.Path())
+	if len(project.Args) > 0 {
+		fmt.Fprintf(w, "Pass-through: %s\n", strings.Join(project.Args, " "))
+	}
+	for _, inv := range plan {
+		fmt.Fprintf(w, "\nStep: %s\n", inv.Step)
+		fmt.Fprintf(w, "  Command:   %s\n", inv.String())
+		fmt.Fprintf(w, "  Directory: %s\n", inv.Dir)
+		if len(inv.Env) > 0 {
+			fmt.Fprintf(w, "  Env:       %s\n", strings.Join(inv.Env, " "))
+		}
+		if inv.Content != "" {
+			fmt.Fprintf(w, "  Content:   %d bytes\n", len(inv.Content))
+		}
+	}
+}
+
+// recordHistory appends the outcome of a bootstrap run to the history log.
+// Failing to write the log does not fail the run.
+func recordHistory(cmd *cobra.Command, provider providers.Provider, project providers.Project, options map[string]string, runErr error) {
+	entry := util.HistoryEntry{
+		Time:        time.Now().UTC(),
+		Provider:    provider.Name(),
+		Project:     project.Name,
+		Path:        project.Path(),
+		Options:     options,
+		Passthrough: project.Args,
+	}
+	if runErr != nil {
+		entry.Error = runErr.Error()
+	}
+
+	if err := util.AppendHistory(entry); err != nil && verbose {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Warning: failed to record history: %v\n", err)
+	}
+}
+
+// absDir resolves dir against the current directory
+func absDir(dir string) string {
+	if dir == "" {
+		dir = "."
+	}
+	if abs, err := filepath.Abs(dir); err == nil {
+		return abs
+	}
+	if wd, err := os.Getwd(); err == nil {
+		return filepath.Join(wd, dir)
+	}
+	return dir
+}
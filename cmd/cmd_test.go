@@ -1,10 +1,25 @@
 package cmd
 
 import (
+	"archive/zip"
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
 	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/sharik709/bootstraper/providers"
+	"github.com/sharik709/bootstraper/util"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 func TestRootCmd(t *testing.T) {
@@ -46,7 +61,7 @@ func TestListCmd(t *testing.T) {
 		r, w, _ := os.Pipe()
 		os.Stdout = w
 
-		listCmd.Run(listCmd, []string{})
+		err := listCmd.RunE(listCmd, []string{})
 
 		// Restore stdout
 		w.Close()
@@ -56,6 +71,10 @@ func TestListCmd(t *testing.T) {
 		io.Copy(&buf, r)
 		output := buf.String()
 
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
 		// Basic check for expected content
 		if len(output) == 0 {
 			t.Error("Expected non-empty output")
@@ -120,3 +139,1122 @@ func TestNewAndProjectCmdArgs(t *testing.T) {
 		}
 	})
 }
+
+func TestDoctorCmd(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	t.Run("Unknown framework is an error", func(t *testing.T) {
+		if err := doctorCmd.RunE(doctorCmd, []string{"bt-framework-that-does-not-exist"}); err == nil {
+			t.Error("Expected error for unknown framework, got nil")
+		}
+	})
+
+	// A provider as an overlay would define it, needing a binary that is
+	// not installed and one that is
+	providers.Register(&providers.ProviderDefinition{
+		ProviderName: "doctor-test",
+		Command:      "bt-missing-binary",
+		DependsOn:    []providers.Dependency{{Command: "bt-missing-binary"}, {Command: "go"}},
+	})
+	t.Cleanup(func() { delete(providers.Registry, "doctor-test") })
+
+	t.Run("Missing dependencies fail the named framework", func(t *testing.T) {
+		out, err := executeCommand(t, "doctor", "doctor-test")
+		if err == nil || !strings.Contains(err.Error(), "cannot run: doctor-test") {
+			t.Errorf("Expected cannot run error, got %v", err)
+		}
+
+		// The dependency rows: the first names the framework and its status
+		var missing, found []string
+		for _, line := range strings.Split(out, "\n") {
+			fields := strings.Fields(line)
+			switch {
+			case len(fields) > 2 && fields[2] == "bt-missing-binary":
+				missing = fields
+			case len(fields) > 0 && fields[0] == "go":
+				found = fields
+			}
+		}
+
+		want := "doctor-test missing bt-missing-binary - not found -"
+		if got := strings.Join(missing, " "); got != want {
+			t.Errorf("Expected row %q, got %q in %q", want, got, out)
+		}
+		if len(found) != 4 || !filepath.IsAbs(found[2]) || found[3] == "-" {
+			t.Errorf("Expected go with its path and version, got %v in %q", found, out)
+		}
+	})
+
+	t.Run("Missing dependencies in JSON output", func(t *testing.T) {
+		out, err := executeCommand(t, "doctor", "doctor-test", "-o", "json")
+		if err == nil || !strings.Contains(err.Error(), "cannot run: doctor-test") {
+			t.Errorf("Expected cannot run error, got %v", err)
+		}
+
+		var infos []providerInfo
+		if err := json.Unmarshal([]byte(out), &infos); err != nil {
+			t.Fatalf("Expected JSON output, got %q: %v", out, err)
+		}
+		if len(infos) != 1 || infos[0].Status != "missing" || len(infos[0].Dependencies) != 2 {
+			t.Fatalf("Expected one missing framework with two dependencies, got %+v", infos)
+		}
+		if dep := infos[0].Dependencies[0]; dep.Name != "bt-missing-binary" || dep.Found || dep.Path != "" {
+			t.Errorf("Expected bt-missing-binary not to be found, got %+v", dep)
+		}
+		if dep := infos[0].Dependencies[1]; dep.Name != "go" || !dep.Found || dep.Path == "" || dep.Version == "" {
+			t.Errorf("Expected go to be found with a version, got %+v", dep)
+		}
+	})
+}
+
+// executeCommand runs the root command with args and returns its output.
+// Flags are reset first since cobra keeps their values between executions.
+func executeCommand(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+
+	var reset func(c *cobra.Command)
+	resetFlag := func(f *pflag.Flag) {
+		if sv, ok := f.Value.(pflag.SliceValue); ok {
+			sv.Replace(nil)
+		} else {
+			f.Value.Set(f.DefValue)
+		}
+		f.Changed = false
+	}
+	reset = func(c *cobra.Command) {
+		// Init forgets where "--" was seen, which Parse does not reset
+		c.Flags().Init(c.Name(), pflag.ContinueOnError)
+		c.Flags().VisitAll(resetFlag)
+		c.PersistentFlags().VisitAll(resetFlag)
+		for _, child := range c.Commands() {
+			reset(child)
+		}
+	}
+	reset(rootCmd)
+
+	var buf bytes.Buffer
+	rootCmd.SetOut(&buf)
+	rootCmd.SetErr(io.Discard)
+	defer func() {
+		rootCmd.SetOut(nil)
+		rootCmd.SetErr(nil)
+		rootCmd.SetArgs(nil)
+	}()
+
+	err := execute(args)
+	return buf.String(), err
+}
+
+// dryRunOptions runs a JSON dry run and returns the resolved options
+func dryRunOptions(t *testing.T, args ...string) map[string]string {
+	t.Helper()
+
+	out, err := executeCommand(t, append(args, "--dry-run", "--output", "json")...)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var plan dryRunPlan
+	if err := json.Unmarshal([]byte(out), &plan); err != nil {
+		t.Fatalf("Expected JSON output, got %q: %v", out, err)
+	}
+	return plan.Options
+}
+
+func TestDryRun(t *testing.T) {
+	t.Run("Dry run prints the plan as JSON", func(t *testing.T) {
+		out, err := executeCommand(t, "new", "go", "svc", "--template=pkg", "--dry-run", "--output", "json")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		var plan dryRunPlan
+		if err := json.Unmarshal([]byte(out), &plan); err != nil {
+			t.Fatalf("Expected JSON output, got %q: %v", out, err)
+		}
+
+		if plan.Provider != "go" || plan.Project != "svc" {
+			t.Errorf("Unexpected plan header: %+v", plan)
+		}
+
+		var steps []string
+		for _, step := range plan.Steps {
+			steps = append(steps, step.Step)
+		}
+		expected := []string{"create project directory", "main", "create pkg layout"}
+		if strings.Join(steps, ",") != strings.Join(expected, ",") {
+			t.Errorf("Expected steps %v, got %v", expected, steps)
+		}
+
+		if _, err := os.Stat("svc"); !os.IsNotExist(err) {
+			t.Errorf("Expected dry run not to create the project")
+		}
+	})
+}
+
+func TestConfigDefaults(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	config := `{"defaults": {"go": {"template": "lib", "module": "example.com/svc"}}}`
+	if err := os.WriteFile(filepath.Join(home, ".bootstraperrc"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Configured defaults are applied", func(t *testing.T) {
+		options := dryRunOptions(t, "new", "go", "svc")
+		if options["template"] != "lib" || options["module"] != "example.com/svc" {
+			t.Errorf("Expected configured defaults, got %v", options)
+		}
+	})
+
+	t.Run("Flags win over configured defaults", func(t *testing.T) {
+		options := dryRunOptions(t, "new", "go", "svc", "--template=pkg")
+		if options["template"] != "pkg" || options["module"] != "example.com/svc" {
+			t.Errorf("Expected flag to override the default, got %v", options)
+		}
+	})
+
+	t.Run("--no-defaults ignores configured defaults", func(t *testing.T) {
+		options := dryRunOptions(t, "new", "go", "svc", "--no-defaults")
+		if options["template"] != "cmd" {
+			t.Errorf("Expected the provider default 'cmd', got %v", options)
+		}
+		if _, ok := options["module"]; ok {
+			t.Errorf("Expected no module option, got %v", options)
+		}
+	})
+}
+
+func TestProjectPlacement(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	projectDir := filepath.Join(home, "work")
+	config := `{"projectDir": "` + filepath.ToSlash(projectDir) + `"}`
+	if err := os.WriteFile(filepath.Join(home, ".bootstraperrc"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	dryRunPath := func(t *testing.T, args ...string) string {
+		t.Helper()
+		out, err := executeCommand(t, append(args, "--dry-run", "--output", "json")...)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		var plan dryRunPlan
+		if err := json.Unmarshal([]byte(out), &plan); err != nil {
+			t.Fatalf("Expected JSON output, got %q: %v", out, err)
+		}
+		return plan.Path
+	}
+
+	wd, _ := os.Getwd()
+	other := t.TempDir()
+
+	cases := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{"projectDir is the default", []string{"new", "go", "svc"}, filepath.Join(projectDir, "svc")},
+		{"--dir overrides projectDir", []string{"new", "go", "svc", "--dir", other}, filepath.Join(other, "svc")},
+		{"--here uses the current directory", []string{"new", "go", "svc", "--here"}, filepath.Join(wd, "svc")},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if path := dryRunPath(t, c.args...); path != c.expected {
+				t.Errorf("Expected project at %s, got %s", c.expected, path)
+			}
+		})
+	}
+
+	t.Run("--dir and --here are exclusive", func(t *testing.T) {
+		if _, err := executeCommand(t, "new", "go", "svc", "--here", "--dir", other, "--dry-run"); err == nil {
+			t.Error("Expected error, got nil")
+		}
+	})
+
+	t.Run("Project names cannot be paths", func(t *testing.T) {
+		if _, err := executeCommand(t, "new", "go", "../svc", "--dry-run"); err == nil {
+			t.Error("Expected error, got nil")
+		}
+	})
+}
+
+func TestProviderSubcommands(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	findNew := func(name string) *cobra.Command {
+		for _, c := range newCmd.Commands() {
+			if c.Name() == name {
+				return c
+			}
+		}
+		return nil
+	}
+
+	t.Run("Each framework has a new subcommand", func(t *testing.T) {
+		for _, name := range []string{"next", "go", "flutter"} {
+			if findNew(name) == nil {
+				t.Errorf("Expected to find 'new %s' subcommand", name)
+			}
+		}
+	})
+
+	t.Run("Subcommands only have their own option flags", func(t *testing.T) {
+		next := findNew("next")
+		if next == nil {
+			t.Fatal("Expected to find 'new next' subcommand")
+		}
+		if next.Flags().Lookup("typescript") == nil {
+			t.Error("Expected 'new next' to have a --typescript flag")
+		}
+		if next.Flags().Lookup("org") != nil {
+			t.Error("Expected 'new next' to have no --org flag")
+		}
+	})
+
+	t.Run("Options of other frameworks are rejected", func(t *testing.T) {
+		if _, err := executeCommand(t, "new", "next", "app", "--org=com.example", "--dry-run"); err == nil {
+			t.Error("Expected error for 'new next --org', got nil")
+		}
+		if _, err := executeCommand(t, "project", "app", "--next", "--org=com.example", "--dry-run"); err == nil {
+			t.Error("Expected error for 'project --next --org', got nil")
+		}
+	})
+
+	t.Run("Unknown framework is an error", func(t *testing.T) {
+		if _, err := executeCommand(t, "new", "bt-framework-that-does-not-exist", "app"); err == nil {
+			t.Error("Expected error for unknown framework, got nil")
+		}
+	})
+}
+
+func TestPassthroughArgs(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	config := `{"versionSources": {"npm": "` + newNpmServer(t) + `"}}`
+	if err := os.WriteFile(filepath.Join(home, ".bootstraperrc"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Args after -- are appended to the generator", func(t *testing.T) {
+		out, err := executeCommand(t, "new", "next", "app", "--typescript", "--dry-run", "--output", "json", "--", "--use-pnpm", "--import-alias", "@/*")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		var plan dryRunPlan
+		if err := json.Unmarshal([]byte(out), &plan); err != nil {
+			t.Fatalf("Expected JSON output, got %q: %v", out, err)
+		}
+
+		expected := []string{"--use-pnpm", "--import-alias", "@/*"}
+		if strings.Join(plan.Passthrough, " ") != strings.Join(expected, " ") {
+			t.Errorf("Expected pass-through args %v, got %v", expected, plan.Passthrough)
+		}
+		args := plan.Steps[len(plan.Steps)-1].Args
+		if len(args) < 3 || strings.Join(args[len(args)-3:], " ") != strings.Join(expected, " ") {
+			t.Errorf("Expected main step to end with %v, got %v", expected, args)
+		}
+	})
+
+	t.Run("Providers can refuse pass-through args", func(t *testing.T) {
+		if _, err := executeCommand(t, "new", "go", "svc", "--dry-run", "--", "-x"); err == nil {
+			t.Error("Expected error for go pass-through args, got nil")
+		}
+	})
+
+	t.Run("Pass-through args do not count as positional args", func(t *testing.T) {
+		if _, err := executeCommand(t, "new", "next", "--dry-run", "--", "app"); err == nil {
+			t.Error("Expected error for missing project name, got nil")
+		}
+	})
+}
+
+func TestNewWizard(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	config := `{"defaults": {"go": {"template": "lib"}}}`
+	if err := os.WriteFile(filepath.Join(home, ".bootstraperrc"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	runWizardWith := func(t *testing.T, input string, args ...string) (string, error) {
+		t.Helper()
+		rootCmd.SetIn(strings.NewReader(input))
+		defer rootCmd.SetIn(nil)
+		return executeCommand(t, append([]string{"new"}, args...)...)
+	}
+
+	t.Run("Wizard prompts for framework, name and options", func(t *testing.T) {
+		// Filter, pick go, reject an invalid name, then answer module and
+		// keep the configured template default and the latest version
+		input := "compiled\n../svc\nsvc\ngithub.com/me/svc\n\n\n"
+		out, err := runWizardWith(t, input, "--dry-run")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v\nOutput: %s", err, out)
+		}
+
+		if !strings.Contains(out, "invalid project name") {
+			t.Errorf("Expected invalid name to be reported, got %q", out)
+		}
+		if !strings.Contains(out, "[lib]") {
+			t.Errorf("Expected configured default to be offered, got %q", out)
+		}
+
+		expected := "bt new go svc --module=github.com/me/svc --template=lib --dry-run"
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected equivalent command %q, got %q", expected, out)
+		}
+	})
+
+	t.Run("Invalid option values are asked again", func(t *testing.T) {
+		input := "go\nsvc\n\nweb\n2\n\n"
+		out, err := runWizardWith(t, input, "--dry-run")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v\nOutput: %s", err, out)
+		}
+		if !strings.Contains(out, `invalid value "web"`) {
+			t.Errorf("Expected invalid value to be reported, got %q", out)
+		}
+		if !strings.Contains(out, "bt new go svc --template=pkg --dry-run") {
+			t.Errorf("Expected numbered choice to select pkg, got %q", out)
+		}
+	})
+
+	t.Run("Choosing the schema default over a configured default", func(t *testing.T) {
+		// cmd is the schema default, lib the configured one
+		input := "go\nsvc\n\ncmd\n\n"
+		out, err := runWizardWith(t, input, "--dry-run")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v\nOutput: %s", err, out)
+		}
+		if !strings.Contains(out, "bt new go svc --template=cmd --dry-run") {
+			t.Errorf("Expected --template=cmd in the equivalent command, got %q", out)
+		}
+	})
+
+	t.Run("Running out of input is an error", func(t *testing.T) {
+		if _, err := runWizardWith(t, "next\n", "--dry-run"); err == nil {
+			t.Error("Expected error, got nil")
+		}
+	})
+}
+
+func TestCompletion(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	config := `{"templates": {"web": {"source": "github:me/web", "description": "Web starter"}}}`
+	if err := os.WriteFile(filepath.Join(home, ".bootstraperrc"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	complete := func(t *testing.T, args ...string) []string {
+		t.Helper()
+		out, err := executeCommand(t, append([]string{cobra.ShellCompRequestCmd}, args...)...)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		// Drop descriptions and the trailing directive line
+		var values []string
+		for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+			if strings.HasPrefix(line, ":") {
+				break
+			}
+			values = append(values, strings.SplitN(line, "\t", 2)[0])
+		}
+		return values
+	}
+
+	cases := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{"Framework names", []string{"new", "ne"}, "next"},
+		{"Enum option values", []string{"new", "angular", "app", "--style", ""}, "scss"},
+		{"Enum option values for the selected framework", []string{"project", "app", "--angular", "--style", ""}, "scss"},
+		{"Template names", []string{"template", "use", ""}, "web"},
+		{"Config default paths", []string{"config", "get", "defaults.next.t"}, "defaults.next.typescript"},
+		{"Config default values", []string{"config", "set", "defaults.go.template", ""}, "pkg"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			values := complete(t, c.args...)
+			if !containsArg(values, c.expected) {
+				t.Errorf("Expected completions to include %q, got %v", c.expected, values)
+			}
+		})
+	}
+
+	t.Run("List options complete the remaining items", func(t *testing.T) {
+		opt := providers.Option{Name: "platforms", Type: providers.OptionList, Values: []string{"ios", "android", "web"}}
+		values, _ := completeOptionValue(opt, "ios,")
+		expected := []string{"ios,android", "ios,web"}
+		if strings.Join(values, " ") != strings.Join(expected, " ") {
+			t.Errorf("Expected %v, got %v", expected, values)
+		}
+	})
+}
+
+func TestStructuredOutput(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	config := `{
+  "defaults": {"next": {"typescript": true}},
+  "templates": {
+    "web": {"source": "github:me/web", "description": "Web starter"},
+    "api": {"source": "github:me/api", "description": "API starter"},
+    "cli": {"source": "github:me/cli", "description": "CLI starter"}
+  }
+}`
+	if err := os.WriteFile(filepath.Join(home, ".bootstraperrc"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("List describes providers as JSON", func(t *testing.T) {
+		out, err := executeCommand(t, "list", "--output", "json")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		var infos []providerInfo
+		if err := json.Unmarshal([]byte(out), &infos); err != nil {
+			t.Fatalf("Expected JSON output, got %q: %v", out, err)
+		}
+
+		var angular *providerInfo
+		for i := range infos {
+			if infos[i].Name == "angular" {
+				angular = &infos[i]
+			}
+		}
+		if angular == nil {
+			t.Fatal("Expected angular in the list")
+		}
+		if angular.Status == "" || len(angular.Dependencies) == 0 {
+			t.Errorf("Expected dependency status, got %+v", angular)
+		}
+
+		found := false
+		for _, opt := range angular.Options {
+			if opt.Name == "style" && opt.Type == "enum" && len(opt.Values) > 0 {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("Expected style enum in options, got %+v", angular.Options)
+		}
+	})
+
+	t.Run("Template list is sorted", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			out, err := executeCommand(t, "template", "list", "-o", "json")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			var infos []templateInfo
+			if err := json.Unmarshal([]byte(out), &infos); err != nil {
+				t.Fatalf("Expected JSON output, got %q: %v", out, err)
+			}
+
+			var names []string
+			for _, info := range infos {
+				names = append(names, info.Name)
+			}
+			if strings.Join(names, ",") != "api,cli,web" {
+				t.Errorf("Expected templates api,cli,web, got %v", names)
+			}
+		}
+	})
+
+	t.Run("Config get supports yaml", func(t *testing.T) {
+		out, err := executeCommand(t, "config", "get", "defaults", "-o", "yaml")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		expected := "next:\n  typescript: true\n"
+		if out != expected {
+			t.Errorf("Expected %q, got %q", expected, out)
+		}
+	})
+
+	t.Run("Unknown formats are rejected", func(t *testing.T) {
+		if _, err := executeCommand(t, "list", "-o", "xml"); err == nil {
+			t.Error("Expected error, got nil")
+		}
+	})
+}
+
+func TestInfoCmd(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	config := `{"defaults": {"flutter": {"org": "com.example"}}}`
+	if err := os.WriteFile(filepath.Join(home, ".bootstraperrc"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Info shows options and configured defaults", func(t *testing.T) {
+		out, err := executeCommand(t, "info", "flutter")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		for _, s := range []string{"flutter create", "--platforms", "android, ios", "org = com.example"} {
+			if !strings.Contains(out, s) {
+				t.Errorf("Expected output to contain %q, got %q", s, out)
+			}
+		}
+	})
+
+	t.Run("Info supports JSON", func(t *testing.T) {
+		out, err := executeCommand(t, "info", "flutter", "-o", "json")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		var detail struct {
+			Name     string                 `json:"name"`
+			Command  string                 `json:"command"`
+			Args     []string               `json:"args"`
+			Defaults map[string]interface{} `json:"defaults"`
+		}
+		if err := json.Unmarshal([]byte(out), &detail); err != nil {
+			t.Fatalf("Expected JSON output, got %q: %v", out, err)
+		}
+		if detail.Name != "flutter" || detail.Command != "flutter" || len(detail.Args) == 0 {
+			t.Errorf("Expected flutter command template, got %+v", detail)
+		}
+		if detail.Defaults["org"] != "com.example" {
+			t.Errorf("Expected configured org default, got %v", detail.Defaults)
+		}
+	})
+
+	t.Run("Unknown framework is an error", func(t *testing.T) {
+		if _, err := executeCommand(t, "info", "bt-framework-that-does-not-exist"); err == nil {
+			t.Error("Expected error, got nil")
+		}
+	})
+}
+
+func TestAliases(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	config := `{"aliases": {"svc": "go", "web": "next"}, "versionSources": {"npm": "` + newNpmServer(t) + `"}}`
+	if err := os.WriteFile(filepath.Join(home, ".bootstraperrc"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// Configured aliases are registered at startup, before HOME is set here,
+	// so register them again from this configuration and rebuild the
+	// framework subcommands with them
+	registered := providers.RegisterConfigAliases()
+	addProviderCmds()
+	t.Cleanup(func() {
+		for _, alias := range registered {
+			providers.UnregisterAlias(alias)
+		}
+		addProviderCmds()
+	})
+	if len(registered) != 2 || registered[0] != "svc" || registered[1] != "web" {
+		t.Fatalf("Expected the svc and web aliases from the configuration, got %v", registered)
+	}
+
+	t.Run("Registry aliases work as subcommands", func(t *testing.T) {
+		out, err := executeCommand(t, "new", "golang", "app", "--dry-run")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !strings.Contains(out, "go mod init") {
+			t.Errorf("Expected go plan, got %q", out)
+		}
+	})
+
+	t.Run("Names and aliases match in any case", func(t *testing.T) {
+		tests := []struct {
+			args []string
+			want string
+		}{
+			{[]string{"new", "NextJS", "app", "--dry-run"}, "create-next-app"},
+			{[]string{"new", "Golang", "app", "--module=example.com/app", "--dry-run"}, "go mod init example.com/app"},
+			{[]string{"new", "GO", "app", "--dry-run"}, "go mod init"},
+			{[]string{"-v", "new", "--dir", "/tmp", "Golang", "app", "--dry-run"}, "go mod init"},
+		}
+		for _, tt := range tests {
+			out, err := executeCommand(t, tt.args...)
+			if err != nil {
+				t.Errorf("Unexpected error for %v: %v", tt.args, err)
+				continue
+			}
+			if !strings.Contains(out, tt.want) {
+				t.Errorf("Expected %q for %v, got %q", tt.want, tt.args, out)
+			}
+		}
+	})
+
+	t.Run("Other commands still match exactly", func(t *testing.T) {
+		if _, err := executeCommand(t, "LIST"); err == nil {
+			t.Error("Expected error for LIST, got nil")
+		}
+	})
+
+	t.Run("Configured aliases resolve", func(t *testing.T) {
+		out, err := executeCommand(t, "info", "svc")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !strings.Contains(out, "go mod init") {
+			t.Errorf("Expected go details, got %q", out)
+		}
+
+		out, err = executeCommand(t, "new", "Web", "app", "--typescript", "--dry-run", "--", "--use-pnpm")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !strings.Contains(out, "create-next-app@17.1.0 app --ts --use-pnpm") {
+			t.Errorf("Expected next plan with its flags and pass-through args, got %q", out)
+		}
+
+		out, err = executeCommand(t, "config", "get", "aliases", "-o", "json")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !strings.Contains(out, `"svc": "go"`) {
+			t.Errorf("Expected configured alias, got %q", out)
+		}
+	})
+
+	t.Run("Typos suggest close matches", func(t *testing.T) {
+		_, err := executeCommand(t, "new", "larvel", "app")
+		if err == nil || !strings.Contains(err.Error(), "Did you mean this?\n\tlaravel") {
+			t.Errorf("Expected laravel suggestion, got %v", err)
+		}
+	})
+}
+
+// writeTemplate creates the template files in dir, keyed by slash-separated
+// path, along with their parent directories
+func writeTemplate(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestTemplateUseLocal(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	src := filepath.Join(home, "templates", "web")
+	writeTemplate(t, src, map[string]string{
+		"index.html": "<h1>hello</h1>\n",
+		".btignore":  "*.log\n",
+		"debug.log":  "noise\n",
+		".git/HEAD":  "ref: refs/heads/main\n",
+	})
+
+	projects := filepath.Join(home, "projects")
+	for _, source := range []string{src, "file://" + filepath.ToSlash(src)} {
+		if _, err := executeCommand(t, "template", "add", "web", source); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if _, err := executeCommand(t, "template", "use", "web", "site", "--dir", projects); err != nil {
+			t.Fatalf("Unexpected error using %s: %v", source, err)
+		}
+
+		site := filepath.Join(projects, "site")
+		if _, err := os.Stat(filepath.Join(site, "index.html")); err != nil {
+			t.Errorf("Expected index.html to be copied from %s: %v", source, err)
+		}
+		for _, name := range []string{".git", "debug.log", ".btignore"} {
+			if _, err := os.Stat(filepath.Join(site, name)); !os.IsNotExist(err) {
+				t.Errorf("Expected %s to be skipped", name)
+			}
+		}
+		os.RemoveAll(site)
+	}
+}
+
+func TestTemplateUseArchive(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create("starter-main/index.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fmt.Fprint(w, "<h1>hello</h1>\n")
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	archive := buf.Bytes()
+	sum := sha256.Sum256(archive)
+	checksum := hex.EncodeToString(sum[:])
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(archive)
+	}))
+	defer server.Close()
+
+	projects := filepath.Join(home, "projects")
+
+	t.Run("Archive is verified and extracted", func(t *testing.T) {
+		if _, err := executeCommand(t, "template", "add", "starter", server.URL+"/starter.zip", "--sha256", checksum); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if _, err := executeCommand(t, "template", "use", "starter", "site", "--dir", projects); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(projects, "site", "index.html")); err != nil {
+			t.Errorf("Expected index.html at the project root: %v", err)
+		}
+	})
+
+	t.Run("Checksum mismatch is an error", func(t *testing.T) {
+		if _, err := executeCommand(t, "template", "add", "starter", server.URL+"/starter.zip", "--sha256", strings.Repeat("0", 64)); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		_, err := executeCommand(t, "template", "use", "starter", "other", "--dir", projects)
+		if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+			t.Errorf("Expected checksum mismatch, got %v", err)
+		}
+	})
+
+	t.Run("Checksums only apply to archives", func(t *testing.T) {
+		if _, err := executeCommand(t, "template", "add", "local", home, "--sha256", checksum); err == nil {
+			t.Error("Expected error, got nil")
+		}
+	})
+}
+
+func TestTemplateUseGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+
+	work := filepath.Join(home, "work")
+	writeTemplate(t, work, map[string]string{"api/main.go": "package main\n"})
+	bare := filepath.Join(home, "templates.git")
+	for _, args := range [][]string{
+		{"-C", work, "init", "--quiet"},
+		{"-C", work, "add", "."},
+		{"-C", work, "-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "-m", "init"},
+		{"-C", work, "tag", "v1"},
+		{"clone", "--quiet", "--bare", work, bare},
+	} {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	commit, err := exec.Command("git", "-C", work, "rev-parse", "HEAD").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	projects := filepath.Join(home, "projects")
+	if _, err := executeCommand(t, "template", "add", "api", "file://"+bare+"@v1//api"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := executeCommand(t, "template", "use", "api", "svc", "--dir", projects); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	svc := filepath.Join(projects, "svc")
+	if _, err := os.Stat(filepath.Join(svc, "main.go")); err != nil {
+		t.Errorf("Expected main.go from the api directory: %v", err)
+	}
+	meta, err := util.ReadProjectMetadata(svc)
+	if err != nil {
+		t.Fatalf("Expected project metadata: %v", err)
+	}
+	if meta.Template != "api" || meta.Commit != strings.TrimSpace(string(commit)) {
+		t.Errorf("Expected template api at commit %s, got %+v", commit, meta)
+	}
+}
+
+func TestTemplateManifest(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	src := filepath.Join(home, "templates", "service")
+	writeTemplate(t, src, map[string]string{
+		"bootstraper.json": `{
+			"variables": [
+				{"name": "service", "prompt": "Service name", "default": "{{kebab .Name}}"},
+				{"name": "docker", "type": "bool", "prompt": "Add a Dockerfile", "default": true}
+			],
+			"files": [{"path": "docker/", "when": ".docker"}]
+		}`,
+		"{{.service}}.go":   "package main // {{.service}}\n",
+		"docker/Dockerfile": "FROM scratch\n",
+	})
+	if _, err := executeCommand(t, "template", "add", "service", src); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	projects := filepath.Join(home, "projects")
+	rootCmd.SetIn(strings.NewReader("\nn\n"))
+	defer rootCmd.SetIn(nil)
+	out, err := executeCommand(t, "template", "use", "service", "OrderService", "--dir", projects)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "Service name [order-service]: ") || !strings.Contains(out, "Add a Dockerfile? [Y/n]") {
+		t.Errorf("Expected manifest prompts, got %q", out)
+	}
+
+	project := filepath.Join(projects, "OrderService")
+	data, err := os.ReadFile(filepath.Join(project, "order-service.go"))
+	if err != nil || string(data) != "package main // order-service\n" {
+		t.Errorf("Expected rendered order-service.go, got %q (%v)", data, err)
+	}
+	for _, name := range []string{"docker", "bootstraper.json"} {
+		if _, err := os.Stat(filepath.Join(project, name)); !os.IsNotExist(err) {
+			t.Errorf("Expected %s to be removed", name)
+		}
+	}
+}
+
+func TestTemplateVars(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	src := filepath.Join(home, "templates", "service")
+	writeTemplate(t, src, map[string]string{
+		"bootstraper.json": `{
+			"variables": [
+				{"name": "owner", "required": true, "pattern": "^[a-z]+$"},
+				{"name": "port", "type": "int", "default": 8080},
+				{"name": "docker", "type": "bool", "default": false},
+				{"name": "metrics", "type": "bool"},
+				{"name": "replicas", "type": "int"}
+			]
+		}`,
+		"README.md": "{{.owner}} {{.port}} {{.docker}}\n",
+	})
+	varsFile := filepath.Join(home, "vars.yaml")
+	if err := os.WriteFile(varsFile, []byte("owner: platform\nport: 9000\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := executeCommand(t, "template", "add", "service", src); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	projects := filepath.Join(home, "projects")
+	readme := func(name string) string {
+		data, _ := os.ReadFile(filepath.Join(projects, name, "README.md"))
+		return string(data)
+	}
+
+	t.Run("Flags override the vars file", func(t *testing.T) {
+		_, err := executeCommand(t, "template", "use", "service", "orders", "--dir", projects,
+			"--non-interactive", "--vars-file", varsFile, "--var", "docker=yes", "--var", "port=9100")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if got := readme("orders"); got != "platform 9100 true\n" {
+			t.Errorf("Expected rendered README, got %q", got)
+		}
+
+		meta, err := util.ReadProjectMetadata(filepath.Join(projects, "orders"))
+		if err != nil {
+			t.Fatalf("Expected project metadata: %v", err)
+		}
+		if meta.Answers["owner"] != "platform" || meta.Answers["port"] != "9100" || meta.Answers["docker"] != "true" {
+			t.Errorf("Expected answers to be recorded, got %v", meta.Answers)
+		}
+	})
+
+	t.Run("Answers are reused", func(t *testing.T) {
+		_, err := executeCommand(t, "template", "use", "service", "billing", "--dir", projects,
+			"--non-interactive", "--answers", filepath.Join(projects, "orders"), "--var", "port=9200")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if got := readme("billing"); got != "platform 9200 true\n" {
+			t.Errorf("Expected reused answers, got %q", got)
+		}
+	})
+
+	t.Run("Optional variables without a default may be omitted", func(t *testing.T) {
+		_, err := executeCommand(t, "template", "use", "service", "search", "--dir", projects,
+			"--non-interactive", "--var", "owner=search")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if got := readme("search"); got != "search 8080 false\n" {
+			t.Errorf("Expected rendered README, got %q", got)
+		}
+	})
+
+	t.Run("Missing required variables fail without prompting", func(t *testing.T) {
+		_, err := executeCommand(t, "template", "use", "service", "users", "--dir", projects, "--non-interactive")
+		if err == nil || !strings.Contains(err.Error(), "missing required template variables: owner") {
+			t.Errorf("Expected missing owner, got %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(projects, "users")); !os.IsNotExist(err) {
+			t.Error("Expected no project directory to be created")
+		}
+	})
+
+	t.Run("Invalid values are rejected", func(t *testing.T) {
+		tests := [][]string{
+			{"--var", "owner=Platform"},
+			{"--var", "owner=a", "--var", "unknown=x"},
+			{"--var", "owner"},
+		}
+		for _, args := range tests {
+			args = append([]string{"template", "use", "service", "users", "--dir", projects, "--non-interactive"}, args...)
+			if _, err := executeCommand(t, args...); err == nil {
+				t.Errorf("Expected error for %v, got nil", args)
+			}
+		}
+	})
+}
+
+// newNpmServer stands in for the npm registry, answering every package
+// with the same releases
+func newNpmServer(t *testing.T) string {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"dist-tags": {"latest": "17.1.0"},
+			"versions": {"16.2.0": {}, "17.1.0": {}, "17.0.3": {}, "18.0.0-rc.1": {}}}`)
+	}))
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func TestVersionsCmd(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	config := `{"versionSources": {"npm": "` + newNpmServer(t) + `"}}`
+	if err := os.WriteFile(filepath.Join(home, ".bootstraperrc"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Versions lists releases newest first", func(t *testing.T) {
+		out, err := executeCommand(t, "versions", "angular", "-o", "json")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		var releases providers.Releases
+		if err := json.Unmarshal([]byte(out), &releases); err != nil {
+			t.Fatalf("Expected JSON output, got %q: %v", out, err)
+		}
+		expected := "17.1.0,17.0.3,16.2.0"
+		if strings.Join(releases.Versions, ",") != expected {
+			t.Errorf("Expected stable versions %s, got %v", expected, releases.Versions)
+		}
+	})
+
+	t.Run("Prereleases and limits", func(t *testing.T) {
+		out, err := executeCommand(t, "versions", "angular", "--prerelease", "--limit", "2")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !strings.Contains(out, "18.0.0-rc.1") || strings.Contains(out, "17.0.3") {
+			t.Errorf("Expected the two newest versions including prereleases, got %q", out)
+		}
+	})
+
+	t.Run("--version is validated", func(t *testing.T) {
+		if _, err := executeCommand(t, "new", "angular", "app", "--version=99.0.0", "--dry-run"); err == nil {
+			t.Error("Expected error for unknown version, got nil")
+		}
+		if _, err := executeCommand(t, "new", "angular", "app", "--version=^17", "--dry-run"); err != nil {
+			t.Errorf("Unexpected error for version range: %v", err)
+		}
+	})
+}
+
+func TestVersionPinning(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	config := `{"versionSources": {"npm": "` + newNpmServer(t) + `"}}`
+	if err := os.WriteFile(filepath.Join(home, ".bootstraperrc"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{"Latest is pinned", []string{"new", "angular", "app"}, "17.1.0"},
+		{"Ranges are pinned", []string{"new", "angular", "app", "--version=^16"}, "16.2.0"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			out, err := executeCommand(t, append(c.args, "--dry-run", "-o", "json")...)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			var plan dryRunPlan
+			if err := json.Unmarshal([]byte(out), &plan); err != nil {
+				t.Fatalf("Expected JSON output, got %q: %v", out, err)
+			}
+			if plan.Options["version"] != c.expected {
+				t.Errorf("Expected version %s, got %q", c.expected, plan.Options["version"])
+			}
+			if arg := "@angular/cli@" + c.expected; !containsArg(plan.Steps[0].Args, arg) {
+				t.Errorf("Expected generator args to contain %s, got %v", arg, plan.Steps[0].Args)
+			}
+		})
+	}
+
+	t.Run("Pinned version is recorded in the project", func(t *testing.T) {
+		def := &providers.ProviderDefinition{
+			ProviderName:  "demo",
+			Command:       "go",
+			CommandArgs:   []string{"version"},
+			Options:       map[string]providers.Option{"version": {Type: providers.OptionString}},
+			VersionSource: &providers.VersionSource{Type: "npm", Package: "demo"},
+			Pre:           []providers.Step{{Name: "mkdir", Builtin: providers.BuiltinMkdir, Args: []string{"{{.Name}}"}}},
+		}
+
+		dir := t.TempDir()
+		cmd := &cobra.Command{}
+		addBootstrapFlags(cmd, cmd.Flags())
+		cmd.Flags().Set("dir", dir)
+
+		if err := runProvider(cmd, def, "app", map[string]string{"version": "17.x"}, nil); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		meta, err := util.ReadProjectMetadata(filepath.Join(dir, "app"))
+		if err != nil {
+			t.Fatalf("Expected project metadata: %v", err)
+		}
+		if meta.Provider != "demo" || meta.Version != "17.1.0" || meta.RequestedVersion != "17.x" {
+			t.Errorf("Expected demo 17.1.0 requested as 17.x, got %+v", meta)
+		}
+	})
+}
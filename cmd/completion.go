@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/sharik709/bootstraper/providers"
+	"github.com/sharik709/bootstraper/util"
+	"github.com/spf13/cobra"
+)
+
+// configKeys are the top-level keys accepted by 'bt config get/set'
+var configKeys = []string{"aliases", "cacheDir", "defaults", "projectDir", "registryUrl", "telemetry", "templates", "versionSources"}
+
+// completeProviderNames completes provider names not already given in args
+func completeProviderNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	var names []string
+	for _, provider := range providers.List() {
+		if !containsArg(args, provider.Name()) && strings.HasPrefix(provider.Name(), toComplete) {
+			names = append(names, provider.Name()+"\t"+provider.Description())
+		}
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeTemplateNames completes the first arg with the configured template names
+func completeTemplateNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	config, err := util.LoadConfig()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	var names []string
+	for name, template := range config.Templates {
+		if strings.HasPrefix(name, toComplete) {
+			names = append(names, name+"\t"+template.Description)
+		}
+	}
+	sort.Strings(names)
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeConfigKey completes the first arg with configuration keys,
+// including a defaults.<provider>.<option> path for every provider option
+func completeConfigKey(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var keys []string
+	for _, key := range configKeys {
+		if strings.HasPrefix(key, toComplete) {
+			keys = append(keys, key)
+		}
+	}
+	for _, provider := range providers.List() {
+		for _, opt := range providers.Schema(provider) {
+			key := "defaults." + provider.Name() + "." + opt.Name
+			if strings.HasPrefix(key, toComplete) {
+				keys = append(keys, key+"\t"+opt.Help)
+			}
+		}
+	}
+	return keys, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeConfigSet completes the key and then its value for 'bt config set'
+func completeConfigSet(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	switch len(args) {
+	case 0:
+		return completeConfigKey(cmd, args, toComplete)
+	case 1:
+		if args[0] == "telemetry" {
+			return filterPrefix([]string{"true", "false"}, toComplete), cobra.ShellCompDirectiveNoFileComp
+		}
+		if args[0] == "cacheDir" || args[0] == "projectDir" {
+			return nil, cobra.ShellCompDirectiveFilterDirs
+		}
+
+		parts := strings.Split(args[0], ".")
+		if len(parts) == 3 && parts[0] == "defaults" {
+			if provider, err := providers.Get(parts[1]); err == nil {
+				for _, opt := range providers.Schema(provider) {
+					if opt.Name == parts[2] {
+						return completeOptionValue(opt, toComplete)
+					}
+				}
+			}
+		}
+	}
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
+// registerOptionCompletion completes the values of opt's flag on cmd
+func registerOptionCompletion(cmd *cobra.Command, opt providers.Option) {
+	if opt.Type == providers.OptionBool || len(opt.Values) == 0 {
+		return
+	}
+	cmd.RegisterFlagCompletionFunc(opt.Name, func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completeOptionValue(opt, toComplete)
+	})
+}
+
+// registerProjectOptionCompletion completes the value of the option flag
+// name on the project command from the selected framework, or from every
+// framework declaring it when none is selected yet
+func registerProjectOptionCompletion(cmd *cobra.Command, name string) {
+	cmd.RegisterFlagCompletionFunc(name, func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		var candidates []providers.Provider
+		for _, provider := range providers.List() {
+			if selected, _ := cmd.Flags().GetBool(provider.Name()); selected {
+				candidates = []providers.Provider{provider}
+				break
+			}
+			candidates = append(candidates, provider)
+		}
+
+		var values []string
+		for _, provider := range candidates {
+			for _, opt := range providers.Schema(provider) {
+				if opt.Name != name {
+					continue
+				}
+				completions, _ := completeOptionValue(opt, toComplete)
+				for _, value := range completions {
+					if !containsArg(values, value) {
+						values = append(values, value)
+					}
+				}
+			}
+		}
+		return values, cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+// completeOptionValue completes the allowed values of opt. List items after
+// a comma complete the remaining values.
+func completeOptionValue(opt providers.Option, toComplete string) ([]string, cobra.ShellCompDirective) {
+	switch opt.Type {
+	case providers.OptionBool:
+		return filterPrefix([]string{"true", "false"}, toComplete), cobra.ShellCompDirectiveNoFileComp
+	case providers.OptionList:
+		prefix := ""
+		if i := strings.LastIndex(toComplete, ","); i >= 0 {
+			prefix = toComplete[:i+1]
+		}
+		chosen := strings.Split(prefix, ",")
+
+		var values []string
+		for _, value := range opt.Values {
+			if !containsArg(chosen, value) {
+				values = append(values, prefix+value)
+			}
+		}
+		return filterPrefix(values, toComplete), cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+	}
+	return filterPrefix(opt.Values, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+func filterPrefix(values []string, prefix string) []string {
+	var filtered []string
+	for _, value := range values {
+		if strings.HasPrefix(value, prefix) {
+			filtered = append(filtered, value)
+		}
+	}
+	return filtered
+}
+
+func containsArg(args []string, s string) bool {
+	for _, arg := range args {
+		if arg == s {
+			return true
+		}
+	}
+	return false
+}
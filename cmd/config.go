@@ -28,31 +28,40 @@ For example:
   bt config get defaults.next.typescript
   bt config get telemetry
   bt config get`,
+	ValidArgsFunction: completeConfigKey,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		config, err := util.LoadConfig()
 		if err != nil {
 			return fmt.Errorf("failed to load config: %v", err)
 		}
 
+		format, _ := outputFormat()
+		out := cmd.OutOrStdout()
+
 		if len(args) == 0 {
 			// Show entire config
+			if format != outputTable {
+				return writeStructured(out, format, config)
+			}
 			data, err := json.MarshalIndent(config, "", "  ")
 			if err != nil {
 				return fmt.Errorf("failed to marshal config: %v", err)
 			}
-			fmt.Println(string(data))
+			fmt.Fprintln(out, string(data))
 			return nil
 		}
 
 		// Parse the key path
 		path := strings.Split(args[0], ".")
-		var result interface{} = config
+		var result interface{} = *config
 
 		// Navigate through the config
 		for _, key := range path {
 			switch v := result.(type) {
 			case map[string]interface{}:
 				result = v[key]
+			case map[string]string:
+				result = v[key]
 			case map[string]map[string]interface{}:
 				result = v[key]
 			case util.Config:
@@ -67,6 +76,12 @@ For example:
 					result = v.CacheDir
 				case "projectDir":
 					result = v.ProjectDir
+				case "registryUrl":
+					result = v.RegistryURL
+				case "versionSources":
+					result = v.VersionSources
+				case "aliases":
+					result = v.Aliases
 				default:
 					return fmt.Errorf("key not found: %s", args[0])
 				}
@@ -76,15 +91,18 @@ For example:
 		}
 
 		// Print the result
+		if format != outputTable {
+			return writeStructured(out, format, result)
+		}
 		if result == nil {
-			fmt.Println("null")
+			fmt.Fprintln(out, "null")
 		} else {
 			switch v := result.(type) {
-			case map[string]interface{}, map[string]map[string]interface{}, map[string]util.Template:
+			case map[string]interface{}, map[string]map[string]interface{}, map[string]util.Template, map[string]string:
 				data, _ := json.MarshalIndent(v, "", "  ")
-				fmt.Println(string(data))
+				fmt.Fprintln(out, string(data))
 			default:
-				fmt.Println(v)
+				fmt.Fprintln(out, v)
 			}
 		}
 
@@ -99,8 +117,11 @@ var configSetCmd = &cobra.Command{
 For example:
   bt config set defaults.next.typescript true
   bt config set telemetry false
-  bt config set projectDir ~/Projects`,
-	Args: cobra.ExactArgs(2),
+  bt config set projectDir ~/Projects
+  bt config set versionSources.npm https://npm.example.com
+  bt config set aliases.svc go`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeConfigSet,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		config, err := util.LoadConfig()
 		if err != nil {
@@ -152,6 +173,36 @@ For example:
 			return nil
 		}
 
+		// Framework aliases, e.g. aliases.svc
+		if strings.HasPrefix(key, "aliases.") {
+			if config.Aliases == nil {
+				config.Aliases = make(map[string]string)
+			}
+			config.Aliases[strings.TrimPrefix(key, "aliases.")] = value
+
+			if err := util.SaveConfig(config); err != nil {
+				return fmt.Errorf("failed to save config: %v", err)
+			}
+
+			fmt.Printf("Set %s to %s\n", key, value)
+			return nil
+		}
+
+		// Version resolver base URLs, e.g. versionSources.npm
+		if strings.HasPrefix(key, "versionSources.") {
+			if config.VersionSources == nil {
+				config.VersionSources = make(map[string]string)
+			}
+			config.VersionSources[strings.TrimPrefix(key, "versionSources.")] = value
+
+			if err := util.SaveConfig(config); err != nil {
+				return fmt.Errorf("failed to save config: %v", err)
+			}
+
+			fmt.Printf("Set %s to %s\n", key, value)
+			return nil
+		}
+
 		// Handle other config settings
 		switch key {
 		case "telemetry":
@@ -160,6 +211,8 @@ For example:
 			config.CacheDir = os.ExpandEnv(value)
 		case "projectDir":
 			config.ProjectDir = os.ExpandEnv(value)
+		case "registryUrl":
+			config.RegistryURL = value
 		default:
 			return fmt.Errorf("unknown configuration key: %s", key)
 		}
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/sharik709/bootstraper/providers"
+	"github.com/spf13/cobra"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor [framework...]",
+	Short: "Check which frameworks can be used on this machine",
+	Long: `Check the dependencies of every framework, or only of the given ones,
+and report which frameworks are usable, where each tool was found and
+which version is installed.
+
+Exits with an error when one of the given frameworks cannot run.
+For example:
+  bt doctor
+  bt doctor next laravel`,
+	ValidArgsFunction: completeProviderNames,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		selected := providers.List()
+		if len(args) > 0 {
+			selected = selected[:0:0]
+			for _, name := range args {
+				provider, err := providers.Get(name)
+				if err != nil {
+					return frameworkNotFound(name, err)
+				}
+				selected = append(selected, provider)
+			}
+		}
+
+		if format, _ := outputFormat(); format != outputTable {
+			infos := []providerInfo{}
+			var unusable []string
+			for _, provider := range selected {
+				info := newProviderInfo(provider)
+				if info.Status != "ok" {
+					unusable = append(unusable, provider.Name())
+				}
+				infos = append(infos, info)
+			}
+			if err := writeStructured(cmd.OutOrStdout(), format, infos); err != nil {
+				return err
+			}
+			if len(args) > 0 && len(unusable) > 0 {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("cannot run: %s", strings.Join(unusable, ", "))
+			}
+			return nil
+		}
+
+		w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "FRAMEWORK\tSTATUS\tDEPENDENCY\tREQUIRED\tPATH\tVERSION")
+
+		var unusable []string
+		for _, provider := range selected {
+			status := "ok"
+			if err := provider.CheckDependencies(); err != nil {
+				status = dependencyErrorStatus(err)
+				unusable = append(unusable, provider.Name())
+			}
+
+			deps := providers.Dependencies(provider)
+			if len(deps) == 0 {
+				fmt.Fprintf(w, "%s\t%s\t-\t-\t-\t-\n", provider.Name(), status)
+				continue
+			}
+
+			for i, dep := range deps {
+				name := provider.Name()
+				rowStatus := status
+				if i > 0 {
+					name, rowStatus = "", ""
+				}
+
+				required, path, version := "-", "not found", "-"
+				if dep.Required != "" {
+					required = dep.Required
+				}
+				if dep.Found {
+					path = dep.Path
+					if dep.Version != "" {
+						version = dep.Version
+					}
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", name, rowStatus, dep.Name, required, path, version)
+			}
+		}
+		w.Flush()
+
+		if len(args) > 0 && len(unusable) > 0 {
+			// The table already explains the failure
+			cmd.SilenceUsage = true
+			return fmt.Errorf("cannot run: %s", strings.Join(unusable, ", "))
+		}
+
+		if verbose {
+			fmt.Fprintf(cmd.OutOrStdout(), "\n%d of %d frameworks usable\n", len(selected)-len(unusable), len(selected))
+		}
+		return nil
+	},
+}
+
+// dependencyErrorStatus summarizes a CheckDependencies error for the table
+func dependencyErrorStatus(err error) string {
+	var depErr *providers.DependencyError
+	if !errors.As(err, &depErr) {
+		return "missing"
+	}
+	for _, dep := range depErr.Unmet {
+		if dep.Missing {
+			return "missing"
+		}
+	}
+	return "outdated"
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/sharik709/bootstraper/providers"
+	"github.com/sharik709/bootstraper/util"
+	"github.com/spf13/cobra"
+)
+
+// providerDetail is the structured output of 'bt info'
+type providerDetail struct {
+	providerInfo
+	Command string   `json:"command,omitempty"`
+	Args    []string `json:"args,omitempty"`
+	// VersionSource is where 'bt versions' looks up releases
+	VersionSource *providers.VersionSource `json:"version_source,omitempty"`
+	Defaults      map[string]interface{}   `json:"defaults"`
+}
+
+var infoCmd = &cobra.Command{
+	Use:   "info [framework]",
+	Short: "Show details about a framework",
+	Long: `Show a framework's description, generator command, dependencies,
+supported versions, options and your configured defaults.
+For example:
+  bt info flutter
+  bt info next -o json`,
+	Args: cobra.ExactArgs(1),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return completeProviderNames(cmd, args, toComplete)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		provider, err := providers.Get(args[0])
+		if err != nil {
+			return frameworkNotFound(args[0], err)
+		}
+
+		defaults, err := util.GetDefaultsForProvider(provider.Name())
+		if err != nil {
+			return fmt.Errorf("failed to load defaults: %v", err)
+		}
+
+		detail := providerDetail{
+			providerInfo: newProviderInfo(provider),
+			Defaults:     defaults,
+		}
+		if ct, ok := provider.(providers.CommandTemplater); ok {
+			detail.Command, detail.Args = ct.CommandTemplate()
+		}
+		if rs, ok := provider.(providers.ReleaseSourcer); ok {
+			detail.VersionSource = rs.ReleaseSource()
+		}
+
+		if format, _ := outputFormat(); format != outputTable {
+			return writeStructured(cmd.OutOrStdout(), format, detail)
+		}
+
+		printProviderDetail(cmd.OutOrStdout(), detail)
+		return nil
+	},
+}
+
+// printProviderDetail writes the human-readable 'bt info' view
+func printProviderDetail(out io.Writer, detail providerDetail) {
+	fmt.Fprintf(out, "%s - %s\n\n", detail.Name, detail.Description)
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	if detail.Command != "" {
+		fmt.Fprintf(w, "Command:\t%s\n", providers.Invocation{Command: detail.Command, Args: detail.Args}.String())
+	}
+	fmt.Fprintf(w, "Status:\t%s\n", detail.Status)
+	if len(detail.Versions) > 0 {
+		fmt.Fprintf(w, "Versions:\t%s\n", strings.Join(detail.Versions, ", "))
+	} else if detail.VersionSource != nil {
+		fmt.Fprintf(w, "Versions:\tfrom %s package %s (see 'bt versions %s')\n", detail.VersionSource.Type, detail.VersionSource.Package, detail.Name)
+	} else {
+		fmt.Fprintf(w, "Versions:\tany (latest by default)\n")
+	}
+	w.Flush()
+
+	fmt.Fprintln(out, "\nDependencies:")
+	if len(detail.Dependencies) == 0 {
+		fmt.Fprintln(out, "  none")
+	} else {
+		w = tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "  NAME\tSTATUS\tREQUIRED\tPATH\tVERSION")
+		for _, dep := range detail.Dependencies {
+			status := "ok"
+			switch {
+			case !dep.Found:
+				status = "missing"
+			case !dep.Satisfied:
+				status = "outdated"
+			}
+			fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\n", dep.Name, status, orDash(dep.Required), orDash(dep.Path), orDash(dep.Version))
+		}
+		w.Flush()
+	}
+
+	fmt.Fprintln(out, "\nOptions:")
+	if len(detail.Options) == 0 {
+		fmt.Fprintln(out, "  none")
+	} else {
+		w = tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "  NAME\tTYPE\tDEFAULT\tVALUES\tDESCRIPTION")
+		for _, opt := range detail.Options {
+			help := opt.Help
+			if opt.Required {
+				help += " (required)"
+			}
+			fmt.Fprintf(w, "  --%s\t%s\t%s\t%s\t%s\n", opt.Name, opt.Type, orDash(opt.Default), orDash(strings.Join(opt.Values, ", ")), help)
+		}
+		w.Flush()
+	}
+
+	fmt.Fprintln(out, "\nConfigured defaults:")
+	if len(detail.Defaults) == 0 {
+		fmt.Fprintf(out, "  none (set with 'bt config set defaults.%s.<option> <value>')\n", detail.Name)
+		return
+	}
+
+	names := make([]string, 0, len(detail.Defaults))
+	for name := range detail.Defaults {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(out, "  %s = %s\n", name, util.FormatValue(detail.Defaults[name]))
+	}
+}
+
+// orDash returns s, or "-" when it is empty
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func init() {
+	rootCmd.AddCommand(infoCmd)
+}
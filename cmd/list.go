@@ -10,12 +10,27 @@ import (
 var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all available frameworks",
-	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("Available frameworks:")
-		fmt.Println("---------------------")
+	Long: `List all available frameworks.
+
+With --output json or yaml each framework is described with its options,
+versions, dependencies and whether it can run on this machine.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out := cmd.OutOrStdout()
+
+		if format, _ := outputFormat(); format != outputTable {
+			infos := []providerInfo{}
+			for _, provider := range providers.List() {
+				infos = append(infos, newProviderInfo(provider))
+			}
+			return writeStructured(out, format, infos)
+		}
+
+		fmt.Fprintln(out, "Available frameworks:")
+		fmt.Fprintln(out, "---------------------")
 
 		for _, provider := range providers.List() {
-			fmt.Printf("%-10s - %s\n", provider.Name(), provider.Description())
+			fmt.Fprintf(out, "%-10s - %s\n", provider.Name(), provider.Description())
 		}
+		return nil
 	},
 }
@@ -1,60 +1,172 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/sharik709/bootstraper/providers"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 var newCmd = &cobra.Command{
 	Use:   "new [framework] [project-name]",
 	Short: "Create a new project with the specified framework",
 	Long: `Create a new project using the specified framework.
+Each framework is a subcommand with its own options, see 'bt new [framework] --help'.
+Run 'bt new' without arguments to be asked for the framework, name and options.
 For example:
   bt new next my-app
   bt new vue my-app
   bt new laravel my-app
-  bt new go my-app --module=github.com/username/my-app`,
-	Args: cobra.ExactArgs(2),
+  bt new go my-app --module=github.com/username/my-app
+
+Arguments after "--" are passed to the framework's generator unchanged:
+  bt new next my-app -- --use-pnpm --import-alias "@/*"`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		// No arguments starts the wizard
+		if len(args) == 0 {
+			return nil
+		}
+		return exactArgsBeforeDash(2)(cmd, args)
+	},
+	// Framework names are completed from the subcommands
+	ValidArgsFunction: cobra.NoFileCompletions,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		framework := args[0]
-		projectName := args[1]
+		if len(args) == 0 {
+			return runWizard(cmd)
+		}
 
-		// Get the provider
+		// Known frameworks and their aliases are handled by their subcommands
+		framework := args[0]
 		provider, err := providers.Get(framework)
 		if err != nil {
-			return fmt.Errorf("framework not supported: %s\nRun 'bt list' to see available frameworks", framework)
+			return frameworkNotFound(framework, err)
 		}
+		// Only providers registered after the subcommands were added get here
+		return fmt.Errorf("framework %s has no command", provider.Name())
+	},
+}
 
-		// Collect options from flags
-		options := make(map[string]string)
+// canonicalArgs rewrites the framework in 'bt new <framework> ...' to its
+// provider's name, so framework names and aliases match in any case as in
+// providers.Get. Other command lines are returned unchanged.
+func canonicalArgs(args []string) []string {
+	i := positionalArg(rootCmd, args, 0)
+	if i < 0 || args[i] != newCmd.Name() {
+		return args
+	}
+	j := positionalArg(newCmd, args, i+1)
+	if j < 0 {
+		return args
+	}
 
-		// Process flag values for the chosen framework
-		for optName := range provider.AvailableOptions() {
-			// Check if the flag exists and was set
-			if flag := cmd.Flag(optName); flag != nil && flag.Changed {
-				options[optName] = flag.Value.String()
+	provider, err := providers.Get(args[j])
+	if err != nil || provider.Name() == args[j] {
+		return args
+	}
+	canonical := append([]string{}, args...)
+	canonical[j] = provider.Name()
+	return canonical
+}
+
+// positionalArg returns the index of the first positional argument in args
+// from start, skipping the flags of cmd and their values, or -1
+func positionalArg(cmd *cobra.Command, args []string, start int) int {
+	lookup := func(name string, short bool) *pflag.Flag {
+		for _, flags := range []*pflag.FlagSet{cmd.LocalFlags(), cmd.InheritedFlags()} {
+			if short {
+				if f := flags.ShorthandLookup(name); f != nil {
+					return f
+				}
+			} else if f := flags.Lookup(name); f != nil {
+				return f
 			}
 		}
+		return nil
+	}
+	// Flags without an optional default, such as --dir, consume the next
+	// argument unless given as --flag=value
+	takesValue := func(f *pflag.Flag) bool {
+		return f != nil && f.NoOptDefVal == ""
+	}
 
-		// Bootstrap the project
-		return provider.Bootstrap(projectName, options)
-	},
+	for i := start; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--":
+			return -1
+		case strings.HasPrefix(arg, "--"):
+			if !strings.Contains(arg, "=") && takesValue(lookup(arg[2:], false)) {
+				i++
+			}
+		case strings.HasPrefix(arg, "-"):
+			if len(arg) == 2 && takesValue(lookup(arg[1:], true)) {
+				i++
+			}
+		default:
+			return i
+		}
+	}
+	return -1
 }
 
-func init() {
-	// Create a map to track which flags have been added to avoid duplicates
-	addedFlags := make(map[string]bool)
+// frameworkNotFound returns the error for an unknown framework name,
+// suggesting close matches from err when it is a *providers.NotFoundError
+func frameworkNotFound(name string, err error) error {
+	msg := fmt.Sprintf("framework not supported: %s", name)
 
-	// Add available options for each provider as flags
-	for _, provider := range providers.List() {
-		for option, description := range provider.AvailableOptions() {
-			// Skip if flag already exists
-			if _, exists := addedFlags[option]; !exists {
-				newCmd.Flags().String(option, "", description)
-				addedFlags[option] = true
-			}
+	var notFound *providers.NotFoundError
+	if errors.As(err, &notFound) && len(notFound.Suggestions) > 0 {
+		msg += "\n\nDid you mean this?"
+		for _, suggestion := range notFound.Suggestions {
+			msg += "\n\t" + suggestion
 		}
+		msg += "\n"
+	}
+
+	return fmt.Errorf("%s\nRun 'bt list' to see available frameworks", msg)
+}
+
+// newProviderCmd returns the 'bt new <framework>' subcommand for provider.
+// Its flags, help and validation come only from the provider's own options.
+func newProviderCmd(provider providers.Provider) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     provider.Name() + " [project-name] [-- generator-args...]",
+		Aliases: providers.AliasesFor(provider.Name()),
+		Short:   provider.Description(),
+		Long:    provider.Description(),
+		Example: fmt.Sprintf("  bt new %s my-app", provider.Name()),
+		Args:    exactArgsBeforeDash(1),
+		// The project name is free-form
+		ValidArgsFunction: cobra.NoFileCompletions,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			positional, passthrough := splitPassthrough(cmd, args)
+			return createProject(cmd, provider, positional[0], passthrough)
+		},
+	}
+
+	for _, opt := range providers.Schema(provider) {
+		addOptionFlag(cmd.Flags(), opt)
+		registerOptionCompletion(cmd, opt)
 	}
+
+	return cmd
+}
+
+// addProviderCmds adds a subcommand per framework to newCmd, replacing
+// the ones added before
+func addProviderCmds() {
+	newCmd.RemoveCommand(newCmd.Commands()...)
+	for _, provider := range providers.List() {
+		newCmd.AddCommand(newProviderCmd(provider))
+	}
+}
+
+func init() {
+	// Flags shared by every framework
+	addBootstrapFlags(newCmd, newCmd.PersistentFlags())
+
+	addProviderCmds()
 }
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/sharik709/bootstraper/providers"
+	"github.com/sharik709/bootstraper/util"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// addOptionFlag registers the flag for a single provider option
+func addOptionFlag(flags *pflag.FlagSet, opt providers.Option) {
+	if opt.Type == providers.OptionBool {
+		flags.Bool(opt.Name, false, opt.Usage())
+		return
+	}
+	flags.String(opt.Name, "", opt.Usage())
+}
+
+// addProviderOptionFlags registers one flag per provider option on flags and
+// returns their names. When several providers declare the same option only
+// the first description is kept; the option is a bool flag only if every
+// provider declares it as bool.
+func addProviderOptionFlags(flags *pflag.FlagSet, list []providers.Provider) map[string]bool {
+	var order []string
+	schemas := make(map[string][]providers.Option)
+	for _, provider := range list {
+		for _, opt := range providers.Schema(provider) {
+			if _, exists := schemas[opt.Name]; !exists {
+				order = append(order, opt.Name)
+			}
+			schemas[opt.Name] = append(schemas[opt.Name], opt)
+		}
+	}
+
+	for _, name := range order {
+		opts := schemas[name]
+		isBool := true
+		for _, opt := range opts {
+			if opt.Type != providers.OptionBool {
+				isBool = false
+			}
+		}
+
+		opt := opts[0]
+		if !isBool {
+			opt.Type = providers.OptionString
+		}
+		addOptionFlag(flags, opt)
+	}
+
+	names := make(map[string]bool, len(order))
+	for _, name := range order {
+		names[name] = true
+	}
+	return names
+}
+
+// checkForeignOptions returns an error when a flag in optionFlags was set
+// that is not an option of provider
+func checkForeignOptions(cmd *cobra.Command, provider providers.Provider, optionFlags map[string]bool) error {
+	own := make(map[string]bool)
+	for _, opt := range providers.Schema(provider) {
+		own[opt.Name] = true
+	}
+
+	var err error
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		if err == nil && optionFlags[f.Name] && !own[f.Name] {
+			err = fmt.Errorf("unknown option for %s: --%s\nRun 'bt new %s --help' to see its options", provider.Name(), f.Name, provider.Name())
+		}
+	})
+	return err
+}
+
+// Option value sources, reported with --verbose
+const (
+	sourceDefault = "default"
+	sourceConfig  = "config"
+	sourceFlag    = "flag"
+)
+
+// resolveOptions merges the provider's schema defaults, the user's configured
+// defaults (unless --no-defaults is set) and the flags set on the command
+// line, later sources winning. It returns the validated options and the
+// source of each value.
+func resolveOptions(cmd *cobra.Command, provider providers.Provider) (map[string]string, map[string]string, error) {
+	schema := providers.Schema(provider)
+	known := make(map[string]bool, len(schema))
+
+	options := make(map[string]string)
+	sources := make(map[string]string)
+	for _, opt := range schema {
+		known[opt.Name] = true
+		if opt.Default != "" {
+			options[opt.Name] = opt.Default
+			sources[opt.Name] = sourceDefault
+		}
+	}
+
+	noDefaults, _ := cmd.Flags().GetBool("no-defaults")
+	if !noDefaults {
+		defaults, err := util.GetDefaultsForProvider(provider.Name())
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load defaults: %v", err)
+		}
+
+		for name, value := range defaults {
+			if !known[name] {
+				if verbose {
+					fmt.Fprintf(cmd.ErrOrStderr(), "Warning: ignoring unknown default %s.%s\n", provider.Name(), name)
+				}
+				continue
+			}
+			options[name] = util.FormatValue(value)
+			sources[name] = sourceConfig
+		}
+	}
+
+	for _, opt := range schema {
+		// Check if the flag exists and was set
+		if flag := cmd.Flag(opt.Name); flag != nil && flag.Changed {
+			options[opt.Name] = flag.Value.String()
+			sources[opt.Name] = sourceFlag
+		}
+	}
+
+	validated, err := providers.ValidateOptions(schema, options)
+	if err != nil {
+		return nil, nil, err
+	}
+	return validated, sources, nil
+}
+
+// printOptionSources lists each option value with where it came from
+func printOptionSources(w io.Writer, options, sources map[string]string) {
+	if len(options) == 0 {
+		return
+	}
+
+	names := make([]string, 0, len(options))
+	for name := range options {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(w, "Options:")
+	for _, name := range names {
+		fmt.Fprintf(w, "  %s = %s (%s)\n", name, options[name], sources[name])
+	}
+}
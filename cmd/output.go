@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/sharik709/bootstraper/providers"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats accepted by the global --output flag
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+)
+
+// outputFlag holds the global --output value
+var outputFlag string
+
+// outputFormat returns the validated --output format. "text" is accepted as
+// an alias for table.
+func outputFormat() (string, error) {
+	switch outputFlag {
+	case outputTable, "text", "":
+		return outputTable, nil
+	case outputJSON, outputYAML:
+		return outputFlag, nil
+	}
+	return "", fmt.Errorf("unknown output format: %s (expected table, json or yaml)", outputFlag)
+}
+
+// writeStructured writes v as JSON or YAML. YAML is produced from the JSON
+// encoding so both formats share field names and order.
+func writeStructured(w io.Writer, format string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal output: %v", err)
+	}
+
+	if format == outputJSON {
+		fmt.Fprintln(w, string(data))
+		return nil
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return fmt.Errorf("failed to convert output to yaml: %v", err)
+	}
+	resetStyle(&node)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return fmt.Errorf("failed to marshal output: %v", err)
+	}
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("failed to marshal output: %v", err)
+	}
+
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// resetStyle drops the JSON flow and quoting styles so the node is written
+// as block YAML
+func resetStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		resetStyle(child)
+	}
+}
+
+// providerInfo is the structured description of a provider
+type providerInfo struct {
+	Name         string                       `json:"name"`
+	Description  string                       `json:"description"`
+	Options      []optionInfo                 `json:"options"`
+	Versions     []string                     `json:"versions"`
+	Dependencies []providers.DependencyStatus `json:"dependencies"`
+	// Status is "ok", "missing" or "outdated"
+	Status string `json:"status"`
+}
+
+// optionInfo is the structured description of a provider option
+type optionInfo struct {
+	Name     string   `json:"name"`
+	Type     string   `json:"type"`
+	Help     string   `json:"help,omitempty"`
+	Values   []string `json:"values,omitempty"`
+	Default  string   `json:"default,omitempty"`
+	Required bool     `json:"required,omitempty"`
+}
+
+// newProviderInfo describes provider, checking its dependencies
+func newProviderInfo(provider providers.Provider) providerInfo {
+	info := providerInfo{
+		Name:         provider.Name(),
+		Description:  provider.Description(),
+		Options:      []optionInfo{},
+		Versions:     provider.SupportedVersions(),
+		Dependencies: providers.Dependencies(provider),
+		Status:       "ok",
+	}
+
+	for _, opt := range providers.Schema(provider) {
+		info.Options = append(info.Options, optionInfo{
+			Name:     opt.Name,
+			Type:     string(opt.Type),
+			Help:     opt.Help,
+			Values:   opt.Values,
+			Default:  opt.Default,
+			Required: opt.Required,
+		})
+	}
+	if info.Versions == nil {
+		info.Versions = []string{}
+	}
+	if info.Dependencies == nil {
+		info.Dependencies = []providers.DependencyStatus{}
+	}
+	if err := provider.CheckDependencies(); err != nil {
+		info.Status = dependencyErrorStatus(err)
+	}
+
+	return info
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVarP(&outputFlag, "output", "o", outputTable, "Output format (table, json, yaml)")
+	rootCmd.RegisterFlagCompletionFunc("output", cobra.FixedCompletions([]string{outputTable, outputJSON, outputYAML}, cobra.ShellCompDirectiveNoFileComp))
+}
@@ -7,6 +7,9 @@ import (
 	"github.com/spf13/cobra"
 )
 
+// projectOptionFlags holds the names of the framework option flags
+var projectOptionFlags map[string]bool
+
 var projectCmd = &cobra.Command{
 	Use:   "project [project-name] --[framework]",
 	Short: "Create a new project with the specified framework",
@@ -15,9 +18,11 @@ For example:
   bt project my-app --next
   bt project my-app --vue
   bt project my-app --laravel`,
-	Args: cobra.ExactArgs(1),
+	Args:              exactArgsBeforeDash(1),
+	ValidArgsFunction: cobra.NoFileCompletions,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		projectName := args[0]
+		positional, passthrough := splitPassthrough(cmd, args)
+		projectName := positional[0]
 
 		// Determine which framework flag was used
 		var framework string
@@ -39,39 +44,27 @@ For example:
 			return err
 		}
 
-		// Collect options from flags (exclude framework flags)
-		options := make(map[string]string)
-
-		// Process flag values for the chosen framework
-		for optName := range provider.AvailableOptions() {
-			// Check if the flag exists and was set
-			if flag := cmd.Flag(optName); flag != nil && flag.Changed {
-				options[optName] = flag.Value.String()
-			}
+		// Reject options that belong to other frameworks
+		if err := checkForeignOptions(cmd, provider, projectOptionFlags); err != nil {
+			return err
 		}
 
-		// Bootstrap the project
-		return provider.Bootstrap(projectName, options)
+		return createProject(cmd, provider, projectName, passthrough)
 	},
 }
 
 func init() {
-	// Create a map to track which flags have been added to avoid duplicates
-	addedFlags := make(map[string]bool)
-
 	// Add framework flags
 	for _, provider := range providers.List() {
 		projectCmd.Flags().Bool(provider.Name(), false, fmt.Sprintf("Create a %s project", provider.Name()))
+	}
 
-		// Add framework-specific options to the project command
-		for option, description := range provider.AvailableOptions() {
-			// Skip if flag already exists
-			if _, exists := addedFlags[option]; !exists {
-				projectCmd.Flags().String(option, "", description)
-				addedFlags[option] = true
-			}
-		}
+	// Add framework-specific options to the project command
+	projectOptionFlags = addProviderOptionFlags(projectCmd.Flags(), providers.List())
+	for name := range projectOptionFlags {
+		registerProjectOptionCompletion(projectCmd, name)
 	}
+	addBootstrapFlags(projectCmd, projectCmd.Flags())
 
 	rootCmd.AddCommand(projectCmd)
 }
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/sharik709/bootstraper/providers"
+	"github.com/sharik709/bootstraper/util"
+	"github.com/spf13/cobra"
+)
+
+var registryCmd = &cobra.Command{
+	Use:   "registry",
+	Short: "Manage the provider registry",
+	Long: `Manage the provider registry.
+
+  The built-in registry ships with bt. 'bt registry update' fetches a newer
+  registry from the configured URL and caches it under cacheDir, so new
+  frameworks are available without a new release.`,
+}
+
+var registryUpdateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Fetch the latest provider registry",
+	Long: `Fetch the latest provider registry and store it in the cache directory.
+For example:
+  bt registry update
+  bt registry update --url https://example.com/registry.json --sha256 <checksum>
+  bt config set registryUrl https://example.com/registry.json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := util.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %v", err)
+		}
+
+		url, _ := cmd.Flags().GetString("url")
+		if url == "" {
+			url = config.RegistryURL
+		}
+		checksum, _ := cmd.Flags().GetString("sha256")
+
+		if verbose {
+			fmt.Printf("Fetching provider registry from %s\n", url)
+		}
+
+		result, err := providers.SyncRegistry(providers.SyncOptions{
+			URL:      url,
+			CacheDir: config.CacheDir,
+			SHA256:   checksum,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update registry: %v", err)
+		}
+
+		switch result.Status {
+		case providers.SyncUpdated:
+			fmt.Printf("Registry updated: %d providers (updated_at %s)\n", len(result.Registry.Providers), result.Registry.UpdatedAt)
+		case providers.SyncNotModified:
+			fmt.Printf("Registry is up to date (updated_at %s)\n", result.Registry.UpdatedAt)
+		case providers.SyncOffline:
+			fmt.Printf("Warning: could not reach %s: %v\n", url, result.Err)
+			fmt.Printf("Using %s registry: %d providers (updated_at %s)\n", result.Source, len(result.Registry.Providers), result.Registry.UpdatedAt)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	registryUpdateCmd.Flags().String("url", "", "Registry URL (defaults to the registryUrl config value)")
+	registryUpdateCmd.Flags().String("sha256", "", "Expected SHA-256 checksum of the registry document")
+
+	registryCmd.AddCommand(registryUpdateCmd)
+	rootCmd.AddCommand(registryCmd)
+}
@@ -27,6 +27,11 @@ frameworks, languages, and platforms.
   bt new vue my-vue-app               Create a Vue.js project
   bt list                             List available frameworks
   bt config set defaults.next.typescript true  Set default options`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// Reject unknown --output formats before any work is done
+		_, err := outputFormat()
+		return err
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		// If no subcommand is provided, display help
 		cmd.Help()
@@ -40,7 +45,7 @@ func Execute() error {
 `)
 
 	// Handle any errors during command execution
-	if err := rootCmd.Execute(); err != nil {
+	if err := execute(os.Args[1:]); err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		return err
 	}
@@ -48,6 +53,13 @@ func Execute() error {
 	return nil
 }
 
+// execute runs the command line args, with the framework given to
+// 'bt new' replaced by its provider's name
+func execute(args []string) error {
+	rootCmd.SetArgs(canonicalArgs(args))
+	return rootCmd.Execute()
+}
+
 func init() {
 	// Add global flags
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Enable verbose output")
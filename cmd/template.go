@@ -3,14 +3,25 @@ package cmd
 import (
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
+	"github.com/sharik709/bootstraper/templates"
 	"github.com/sharik709/bootstraper/util"
 	"github.com/spf13/cobra"
 )
 
+// templateInfo is the structured description of a configured template
+type templateInfo struct {
+	Name        string   `json:"name"`
+	Source      string   `json:"source"`
+	Description string   `json:"description"`
+	Tags        []string `json:"tags,omitempty"`
+	SHA256      string   `json:"sha256,omitempty"`
+}
+
 var templateCmd = &cobra.Command{
 	Use:   "template",
 	Short: "Manage custom project templates",
@@ -29,20 +40,43 @@ var templateListCmd = &cobra.Command{
 			return fmt.Errorf("failed to load config: %v", err)
 		}
 
+		names := make([]string, 0, len(config.Templates))
+		for name := range config.Templates {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		out := cmd.OutOrStdout()
+		if format, _ := outputFormat(); format != outputTable {
+			infos := []templateInfo{}
+			for _, name := range names {
+				template := config.Templates[name]
+				infos = append(infos, templateInfo{
+					Name:        name,
+					Source:      template.Source,
+					Description: template.Description,
+					Tags:        template.Tags,
+					SHA256:      template.SHA256,
+				})
+			}
+			return writeStructured(out, format, infos)
+		}
+
 		if len(config.Templates) == 0 {
-			fmt.Println("No templates configured. Use 'bt template add' to add a template.")
+			fmt.Fprintln(out, "No templates configured. Use 'bt template add' to add a template.")
 			return nil
 		}
 
-		fmt.Println("Available templates:")
-		fmt.Println("--------------------")
-		for name, template := range config.Templates {
-			fmt.Printf("%-20s - %s\n", name, template.Description)
-			fmt.Printf("                      Source: %s\n", template.Source)
+		fmt.Fprintln(out, "Available templates:")
+		fmt.Fprintln(out, "--------------------")
+		for _, name := range names {
+			template := config.Templates[name]
+			fmt.Fprintf(out, "%-20s - %s\n", name, template.Description)
+			fmt.Fprintf(out, "                      Source: %s\n", template.Source)
 			if len(template.Tags) > 0 {
-				fmt.Printf("                      Tags: %s\n", strings.Join(template.Tags, ", "))
+				fmt.Fprintf(out, "                      Tags: %s\n", strings.Join(template.Tags, ", "))
 			}
-			fmt.Println()
+			fmt.Fprintln(out)
 		}
 
 		return nil
@@ -57,7 +91,31 @@ var templateAddCmd = &cobra.Command{
   The source can be a git repository, local directory, or archive file.
   For example:
     bt template add my-nextjs github:username/my-nextjs-template
+    bt template add go-api gitlab:org/templates@v2//go-api
+    bt template add internal git@git.example.com:platform/templates.git@main//service
     bt template add my-flask /path/to/local/template
+    bt template add shared file:///mnt/templates/service
+    bt template add api https://example.com/api-template.tar.gz --sha256 <checksum>
+
+  Local templates are copied with their file modes and symlinks. Files
+  matching the template's .btignore (gitignore syntax) and version control
+  directories are skipped, and symlinks pointing outside the template are
+  rejected.
+
+  Git sources are github:, gitlab: or bitbucket: shorthands, ssh URLs,
+  https URLs ending in .git and file:// bare repositories. Append @ref for
+  a branch, tag or commit and //subdir to use one directory of the
+  repository. Repositories are cloned shallowly and the commit used is
+  recorded in the project's .bootstraper.json.
+
+  Archive URLs (.tar.gz, .tgz or .zip) are downloaded into the cache
+  directory and verified against --sha256 when given. A single top-level
+  directory in the archive is stripped.
+
+  A bootstraper.json or bootstraper.yaml manifest at the template root
+  declares variables that are asked for when the template is used, and
+  files included only when a condition holds. File contents and names are
+  rendered with the variables, e.g. {{.service}} or {{pascal .Name}}.
   `,
 	Args: cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -73,6 +131,20 @@ var templateAddCmd = &cobra.Command{
 		// Get other flags
 		description, _ := cmd.Flags().GetString("description")
 		tags, _ := cmd.Flags().GetStringSlice("tags")
+		checksum, _ := cmd.Flags().GetString("sha256")
+		if checksum != "" && !templates.IsArchive(source) {
+			return fmt.Errorf("--sha256 only applies to archive URLs")
+		}
+
+		// Store local paths as absolute paths so the template works from
+		// any directory
+		if templates.IsLocal(source) && !strings.HasPrefix(source, "file://") {
+			dir, err := templates.LocalPath(source)
+			if err != nil {
+				return err
+			}
+			source = dir
+		}
 
 		// Create template
 		if config.Templates == nil {
@@ -83,6 +155,7 @@ var templateAddCmd = &cobra.Command{
 			Source:      source,
 			Description: description,
 			Tags:        tags,
+			SHA256:      checksum,
 		}
 
 		// Save configuration
@@ -96,9 +169,10 @@ var templateAddCmd = &cobra.Command{
 }
 
 var templateRemoveCmd = &cobra.Command{
-	Use:   "remove [name]",
-	Short: "Remove a template",
-	Args:  cobra.ExactArgs(1),
+	Use:               "remove [name]",
+	Short:             "Remove a template",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeTemplateNames,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		name := args[0]
 
@@ -129,8 +203,20 @@ var templateRemoveCmd = &cobra.Command{
 var templateUseCmd = &cobra.Command{
 	Use:   "use [template] [project-name]",
 	Short: "Create a project from a template",
-	Args:  cobra.ExactArgs(2),
-	RunE: func(cmd *cobra.Command, args []string) error {
+	Long: `Create a project from a template.
+
+Variables declared by the template's manifest are asked for, unless they
+are supplied with --var, --vars-file or --answers. Later sources override
+earlier ones: --answers, then --vars-file, then --var. With
+--non-interactive nothing is asked: unset variables use their default and
+missing required variables are an error.
+
+The answers are recorded in the project's .bootstraper.json, so another
+project can be created with the same answers:
+  bt template use service billing --answers ~/Projects/orders`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeTemplateNames,
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
 		templateName := args[0]
 		projectName := args[1]
 
@@ -146,48 +232,210 @@ var templateUseCmd = &cobra.Command{
 			return fmt.Errorf("template '%s' not found", templateName)
 		}
 
-		// Create project directory
-		if err := os.MkdirAll(projectName, 0755); err != nil {
-			return fmt.Errorf("failed to create project directory: %v", err)
+		// Read supplied variables before anything is copied
+		vars, err := templateVars(cmd)
+		if err != nil {
+			return err
 		}
+		nonInteractive, _ := cmd.Flags().GetBool("non-interactive")
+		interactive := !nonInteractive
 
-		// Clone/copy from source
-		if strings.HasPrefix(template.Source, "github:") {
-			// Handle GitHub repository
-			repo := strings.TrimPrefix(template.Source, "github:")
-			gitURL := fmt.Sprintf("https://github.com/%s.git", repo)
+		// Resolve where the project goes; its directory is only created
+		// once the template has been rendered
+		project, err := resolveProject(cmd, projectName)
+		if err != nil {
+			return err
+		}
+		projectPath := project.Path()
 
-			cmd := exec.Command("git", "clone", gitURL, projectName)
-			cmd.Stdout = os.Stdout
-			cmd.Stderr = os.Stderr
+		// The template is fetched and rendered in a staging directory, so
+		// missing or invalid variables fail before the project is touched
+		stage, err := os.MkdirTemp("", "bt-template-*")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(stage)
+		stagePath := filepath.Join(stage, "project")
 
-			fmt.Printf("Creating project from GitHub template: %s\n", repo)
-			if err := cmd.Run(); err != nil {
-				return fmt.Errorf("failed to clone repository: %v", err)
+		commit, err := copyTemplate(config, template, stagePath)
+		if err != nil {
+			return err
+		}
+
+		// Render the template with its manifest's variables
+		manifest, err := templates.LoadManifest(stagePath)
+		if err != nil {
+			return err
+		}
+
+		var answers map[string]string
+		if manifest != nil {
+			if err := manifest.Check(vars, interactive); err != nil {
+				return err
+			}
+
+			p := newPrompter(cmd)
+			ask := func(v templates.Variable, def string) (string, error) {
+				if value, ok := vars[v.Name]; ok {
+					return value, nil
+				}
+				if !interactive {
+					return def, nil
+				}
+				return p.askVariable(v, def)
 			}
 
-			// Remove .git directory
-			if err := os.RemoveAll(filepath.Join(projectName, ".git")); err != nil {
-				fmt.Printf("Warning: failed to remove .git directory: %v\n", err)
+			values, err := manifest.Values(projectName, ask)
+			if err != nil {
+				return err
+			}
+			if err := manifest.Apply(stagePath, values); err != nil {
+				return fmt.Errorf("failed to render template: %v", err)
 			}
-		} else if strings.HasPrefix(template.Source, "http://") || strings.HasPrefix(template.Source, "https://") {
-			// Handle remote URL
-			return fmt.Errorf("direct URL download not implemented yet")
-		} else {
-			// Handle local directory
-			// For simplicity, we'll just copy the directory recursively
-			return fmt.Errorf("local directory copy not implemented yet")
+			answers = manifest.Answers(values)
+		} else if len(vars) > 0 {
+			return fmt.Errorf("template '%s' declares no variables", templateName)
 		}
 
-		fmt.Printf("Project '%s' created from template '%s'.\n", projectName, templateName)
+		// A directory created here is removed again if copying fails
+		if _, statErr := os.Stat(projectPath); os.IsNotExist(statErr) {
+			defer func() {
+				if err != nil {
+					os.RemoveAll(projectPath)
+				}
+			}()
+		}
+		if err := templates.CopyDir(stagePath, projectPath); err != nil {
+			return fmt.Errorf("failed to create project: %v", err)
+		}
+
+		meta := util.ProjectMetadata{
+			Template:  templateName,
+			Source:    template.Source,
+			Commit:    commit,
+			CreatedAt: time.Now().UTC(),
+			BtVersion: Version,
+			Answers:   answers,
+		}
+		if err := util.WriteProjectMetadata(projectPath, meta); err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %v\n", err)
+		}
+
+		fmt.Printf("Project '%s' created from template '%s' at %s\n", projectName, templateName, projectPath)
 		return nil
 	},
 }
 
+// copyTemplate clones, downloads or copies the template's source into
+// projectPath and returns the commit of git sources
+func copyTemplate(config *util.Config, template util.Template, projectPath string) (string, error) {
+	switch {
+	case templates.IsGit(template.Source):
+		// Handle git repository
+		source, err := templates.ParseGitSource(template.Source)
+		if err != nil {
+			return "", err
+		}
+
+		fmt.Printf("Creating project from git template: %s\n", source.URL)
+		commit, err := templates.CopyGit(source, projectPath)
+		if err != nil {
+			return "", fmt.Errorf("failed to clone template: %v", err)
+		}
+		fmt.Printf("Using commit %s\n", commit)
+		return commit, nil
+	case templates.IsArchive(template.Source):
+		// Handle tar.gz or zip archive URL
+		format, err := templates.ArchiveFormat(template.Source)
+		if err != nil {
+			return "", err
+		}
+
+		fmt.Printf("Downloading template archive: %s\n", template.Source)
+		archive, err := templates.FetchArchive(templates.FetchOptions{
+			URL:      template.Source,
+			CacheDir: util.ExpandPath(config.CacheDir),
+			SHA256:   template.SHA256,
+		})
+		if err != nil {
+			return "", err
+		}
+
+		if err := templates.CopyArchive(archive, format, projectPath); err != nil {
+			return "", fmt.Errorf("failed to extract template: %v", err)
+		}
+		return "", nil
+	default:
+		// Handle local directory or file:// URL
+		dir, err := templates.LocalPath(template.Source)
+		if err != nil {
+			return "", err
+		}
+
+		fmt.Printf("Creating project from local template: %s\n", dir)
+		if err := templates.CopyDir(dir, projectPath); err != nil {
+			return "", fmt.Errorf("failed to copy template: %v", err)
+		}
+		return "", nil
+	}
+}
+
+// templateVars merges the variables from --answers, --vars-file and --var,
+// in increasing order of precedence
+func templateVars(cmd *cobra.Command) (map[string]string, error) {
+	vars := make(map[string]string)
+
+	if path, _ := cmd.Flags().GetString("answers"); path != "" {
+		path = util.ExpandPath(path)
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			path = filepath.Join(path, util.ProjectMetadataFile)
+		}
+		meta, err := util.ReadProjectMetadataFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read answers: %v", err)
+		}
+		if len(meta.Answers) == 0 {
+			return nil, fmt.Errorf("no answers recorded in %s", path)
+		}
+		for name, value := range meta.Answers {
+			vars[name] = value
+		}
+	}
+
+	if path, _ := cmd.Flags().GetString("vars-file"); path != "" {
+		values, err := templates.ReadVarsFile(util.ExpandPath(path))
+		if err != nil {
+			return nil, err
+		}
+		for name, value := range values {
+			vars[name] = value
+		}
+	}
+
+	assignments, _ := cmd.Flags().GetStringArray("var")
+	for _, assignment := range assignments {
+		name, value, err := templates.ParseVar(assignment)
+		if err != nil {
+			return nil, err
+		}
+		vars[name] = value
+	}
+
+	return vars, nil
+}
+
 func init() {
 	// Configure template add command
 	templateAddCmd.Flags().String("description", "", "Description of the template")
 	templateAddCmd.Flags().StringSlice("tags", []string{}, "Tags for categorizing the template")
+	templateAddCmd.Flags().String("sha256", "", "Expected SHA-256 checksum of an archive template")
+
+	// Configure template use command
+	addPlacementFlags(templateUseCmd, templateUseCmd.Flags())
+	templateUseCmd.Flags().StringArray("var", []string{}, "Set a template variable (name=value, repeatable)")
+	templateUseCmd.Flags().String("vars-file", "", "Read template variables from a JSON or YAML file")
+	templateUseCmd.Flags().String("answers", "", "Reuse the answers recorded in a project directory or its .bootstraper.json")
+	templateUseCmd.Flags().Bool("non-interactive", false, "Never prompt; use defaults and fail on missing required variables")
 
 	// Add subcommands
 	templateCmd.AddCommand(templateListCmd)
@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/sharik709/bootstraper/providers"
+	"github.com/sharik709/bootstraper/util"
+	"github.com/spf13/cobra"
+)
+
+var versionsCmd = &cobra.Command{
+	Use:   "versions [framework]",
+	Short: "List the published versions of a framework",
+	Long: `List the versions of a framework, newest first, as published to its
+package registry (npm, Packagist, PyPI, the Go module proxy or pub.dev).
+Prereleases are hidden unless --prerelease is given.
+For example:
+  bt versions next
+  bt versions laravel --limit 5
+  bt versions angular -o json`,
+	Args: cobra.ExactArgs(1),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return completeProviderNames(cmd, args, toComplete)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		provider, err := providers.Get(args[0])
+		if err != nil {
+			return frameworkNotFound(args[0], err)
+		}
+
+		releases, err := listReleases(provider)
+		if err != nil {
+			return err
+		}
+
+		prerelease, _ := cmd.Flags().GetBool("prerelease")
+		limit, _ := cmd.Flags().GetInt("limit")
+
+		versions := releases.Versions
+		if !prerelease {
+			versions = releases.Stable()
+		}
+		if limit > 0 && len(versions) > limit {
+			versions = versions[:limit]
+		}
+		if versions == nil {
+			versions = []string{}
+		}
+
+		out := cmd.OutOrStdout()
+		if format, _ := outputFormat(); format != outputTable {
+			return writeStructured(out, format, providers.Releases{Versions: versions, Tags: releases.Tags})
+		}
+
+		if len(versions) == 0 {
+			fmt.Fprintf(out, "No versions known for %s; the latest version is used\n", provider.Name())
+			return nil
+		}
+
+		if len(releases.Tags) > 0 {
+			tags := make([]string, 0, len(releases.Tags))
+			for tag := range releases.Tags {
+				tags = append(tags, tag)
+			}
+			sort.Strings(tags)
+
+			fmt.Fprintln(out, "Tags:")
+			for _, tag := range tags {
+				fmt.Fprintf(out, "  %-10s %s\n", tag, releases.Tags[tag])
+			}
+			fmt.Fprintln(out, "\nVersions:")
+		}
+		for _, version := range versions {
+			fmt.Fprintf(out, "  %s\n", version)
+		}
+		return nil
+	},
+}
+
+// listReleases lists the releases of provider using the configured
+// version source URLs
+func listReleases(provider providers.Provider) (*providers.Releases, error) {
+	config, err := util.LoadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %v", err)
+	}
+	return providers.ListReleases(provider, providers.NewVersionOptions(config))
+}
+
+// resolveVersion pins options["version"] to a concrete release of provider,
+// so the generator runs with a known version, and returns the version that
+// was requested. Providers without a version option are left alone. When
+// the releases cannot be listed the requested version is used unpinned.
+func resolveVersion(cmd *cobra.Command, provider providers.Provider, options map[string]string) (string, error) {
+	requested := options["version"]
+
+	hasVersion := false
+	for _, opt := range providers.Schema(provider) {
+		if opt.Name == "version" {
+			hasVersion = true
+		}
+	}
+	if !hasVersion {
+		return requested, nil
+	}
+
+	label := requested
+	if label == "" {
+		label = "latest"
+	}
+
+	releases, err := listReleases(provider)
+	if err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Warning: could not resolve %s version %s, using it unpinned: %v\n", provider.Name(), label, err)
+		return requested, nil
+	}
+
+	version, err := providers.ResolveVersion(releases, requested)
+	if err != nil {
+		return "", fmt.Errorf("invalid --version for %s: %v\nRun 'bt versions %s' to see available versions", provider.Name(), err, provider.Name())
+	}
+
+	if version != "" {
+		options["version"] = version
+		if verbose {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Resolved %s version %s to %s\n", provider.Name(), label, version)
+		}
+	}
+	return requested, nil
+}
+
+func init() {
+	versionsCmd.Flags().Bool("prerelease", false, "Include prerelease versions")
+	versionsCmd.Flags().Int("limit", 20, "Maximum number of versions to show (0 for all)")
+
+	rootCmd.AddCommand(versionsCmd)
+}
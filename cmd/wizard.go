@@ -0,0 +1,306 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/sharik709/bootstraper/providers"
+	"github.com/sharik709/bootstraper/templates"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// prompter asks questions on out and reads one answer per line from in, so
+// the wizard can be scripted through stdin
+type prompter struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+func newPrompter(cmd *cobra.Command) *prompter {
+	return &prompter{in: bufio.NewReader(cmd.InOrStdin()), out: cmd.OutOrStdout()}
+}
+
+// ask prints question and returns the trimmed answer
+func (p *prompter) ask(question string) (string, error) {
+	fmt.Fprint(p.out, question)
+	line, err := p.in.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		if err == io.EOF {
+			fmt.Fprintln(p.out)
+			return "", errors.New("wizard cancelled: no more input")
+		}
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// selectProvider lets the user pick a provider by number or name. Any other
+// answer filters the list by name and description; a filter matching a
+// single provider selects it.
+func (p *prompter) selectProvider(list []providers.Provider) (providers.Provider, error) {
+	printList := func(filter string) int {
+		matches := 0
+		for i, provider := range list {
+			text := strings.ToLower(provider.Name() + " " + provider.Description())
+			if filter != "" && !strings.Contains(text, strings.ToLower(filter)) {
+				continue
+			}
+			fmt.Fprintf(p.out, "  %2d) %-10s %s\n", i+1, provider.Name(), provider.Description())
+			matches++
+		}
+		return matches
+	}
+
+	fmt.Fprintln(p.out, "Available frameworks:")
+	printList("")
+
+	for {
+		answer, err := p.ask("Framework (number, name or text to filter): ")
+		if err != nil {
+			return nil, err
+		}
+		if answer == "" {
+			continue
+		}
+
+		if n, err := strconv.Atoi(answer); err == nil {
+			if n >= 1 && n <= len(list) {
+				return list[n-1], nil
+			}
+			fmt.Fprintf(p.out, "Please enter a number between 1 and %d\n", len(list))
+			continue
+		}
+
+		var matched []providers.Provider
+		for _, provider := range list {
+			if strings.EqualFold(provider.Name(), answer) {
+				return provider, nil
+			}
+			text := strings.ToLower(provider.Name() + " " + provider.Description())
+			if strings.Contains(text, strings.ToLower(answer)) {
+				matched = append(matched, provider)
+			}
+		}
+
+		switch len(matched) {
+		case 0:
+			fmt.Fprintf(p.out, "No frameworks match %q\n", answer)
+		case 1:
+			return matched[0], nil
+		default:
+			fmt.Fprintf(p.out, "Frameworks matching %q:\n", answer)
+			printList(answer)
+		}
+	}
+}
+
+// askProjectName asks for a project name until a valid one is given
+func (p *prompter) askProjectName() (string, error) {
+	for {
+		name, err := p.ask("Project name: ")
+		if err != nil {
+			return "", err
+		}
+		if err := validateProjectName(name); err != nil {
+			fmt.Fprintln(p.out, err)
+			continue
+		}
+		return name, nil
+	}
+}
+
+// askOption asks for the value of opt, offering def. An empty answer keeps
+// def; the second return value is false when the option is left unset.
+func (p *prompter) askOption(opt providers.Option, def string) (string, bool, error) {
+	help := opt.Help
+	if help == "" {
+		help = opt.Name
+	}
+
+	var question string
+	switch opt.Type {
+	case providers.OptionBool:
+		hint := "y/n"
+		switch def {
+		case "true":
+			hint = "Y/n"
+		case "false":
+			hint = "y/N"
+		}
+		question = fmt.Sprintf("%s? [%s] ", help, hint)
+	case providers.OptionEnum:
+		fmt.Fprintf(p.out, "%s:\n", help)
+		for i, value := range opt.Values {
+			fmt.Fprintf(p.out, "  %2d) %s\n", i+1, value)
+		}
+		question = fmt.Sprintf("Choose %s%s: ", opt.Name, defaultHint(def))
+	default:
+		question = fmt.Sprintf("%s%s: ", help, defaultHint(def))
+	}
+
+	for {
+		answer, err := p.ask(question)
+		if err != nil {
+			return "", false, err
+		}
+
+		if answer == "" {
+			if def != "" {
+				return def, true, nil
+			}
+			if opt.Required {
+				fmt.Fprintf(p.out, "%s is required\n", opt.Name)
+				continue
+			}
+			return "", false, nil
+		}
+
+		if opt.Type == providers.OptionEnum {
+			if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(opt.Values) {
+				answer = opt.Values[n-1]
+			}
+		}
+
+		value, err := opt.Normalize(answer)
+		if err != nil {
+			fmt.Fprintln(p.out, err)
+			continue
+		}
+		return value, true, nil
+	}
+}
+
+// askVariable asks for the value of a template variable until a valid one
+// is given. An empty answer keeps def.
+func (p *prompter) askVariable(v templates.Variable, def string) (string, error) {
+	question := v.Prompt
+	if question == "" {
+		question = v.Name
+	}
+
+	switch v.Type {
+	case templates.VarBool:
+		hint := "y/n"
+		switch def {
+		case "true":
+			hint = "Y/n"
+		case "false":
+			hint = "y/N"
+		}
+		question = fmt.Sprintf("%s? [%s] ", question, hint)
+	case templates.VarEnum:
+		fmt.Fprintf(p.out, "%s:\n", question)
+		for i, choice := range v.Choices {
+			fmt.Fprintf(p.out, "  %2d) %s\n", i+1, choice)
+		}
+		question = fmt.Sprintf("Choose %s%s: ", v.Name, defaultHint(def))
+	default:
+		question = fmt.Sprintf("%s%s: ", question, defaultHint(def))
+	}
+
+	for {
+		answer, err := p.ask(question)
+		if err != nil {
+			return "", err
+		}
+		if answer == "" {
+			answer = def
+		}
+		if v.Type == templates.VarEnum {
+			if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(v.Choices) {
+				answer = v.Choices[n-1]
+			}
+		}
+
+		value, err := v.Normalize(answer)
+		if err != nil {
+			fmt.Fprintln(p.out, err)
+			continue
+		}
+		return value, nil
+	}
+}
+
+func defaultHint(def string) string {
+	if def == "" {
+		return ""
+	}
+	return " [" + def + "]"
+}
+
+// runWizard asks for the framework, project name and options, prints the
+// equivalent non-interactive command and creates the project
+func runWizard(cmd *cobra.Command) error {
+	p := newPrompter(cmd)
+
+	provider, err := p.selectProvider(providers.List())
+	if err != nil {
+		return err
+	}
+
+	projectName, err := p.askProjectName()
+	if err != nil {
+		return err
+	}
+
+	// Schema and configured defaults become the suggested answers
+	defaults, _, err := resolveOptions(cmd, provider)
+	if err != nil {
+		return err
+	}
+
+	schema := providers.Schema(provider)
+	options := make(map[string]string)
+	for _, opt := range schema {
+		value, ok, err := p.askOption(opt, defaults[opt.Name])
+		if err != nil {
+			return err
+		}
+		if ok {
+			options[opt.Name] = value
+		}
+	}
+
+	options, err = providers.ValidateOptions(schema, options)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(p.out, "\nEquivalent command:\n  %s\n\n", equivalentCommand(cmd, provider.Name(), projectName, schema, defaults, options))
+
+	return runProvider(cmd, provider, projectName, options, nil)
+}
+
+// equivalentCommand returns the 'bt new' command line that creates the same
+// project without the wizard. Options are left out only when they match both
+// the schema default and the default the wizard offered, which may come from
+// the configuration; flags given to the wizard itself are kept.
+func equivalentCommand(cmd *cobra.Command, providerName, projectName string, schema []providers.Option, defaults, options map[string]string) string {
+	args := []string{"new", providerName, projectName}
+	for _, opt := range schema {
+		value, ok := options[opt.Name]
+		if !ok || (value == opt.Default && value == defaults[opt.Name]) {
+			continue
+		}
+		if opt.Type == providers.OptionBool && value == "true" {
+			args = append(args, "--"+opt.Name)
+			continue
+		}
+		args = append(args, "--"+opt.Name+"="+value)
+	}
+
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		if f.Value.Type() == "bool" {
+			args = append(args, "--"+f.Name)
+			return
+		}
+		args = append(args, "--"+f.Name+"="+f.Value.String())
+	})
+
+	return providers.Invocation{Command: "bt", Args: args}.String()
+}
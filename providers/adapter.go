@@ -0,0 +1,53 @@
+package providers
+
+// ProviderAdapter implements Provider with function fields
+type ProviderAdapter struct {
+	NameFunc              func() string
+	DescriptionFunc       func() string
+	BootstrapFunc         func(projectName string, options map[string]string) error
+	AvailableOptionsFunc  func() map[string]string
+	CheckDependenciesFunc func() error
+	SupportedVersionsFunc func() []string
+}
+
+func (a *ProviderAdapter) Name() string {
+	if a.NameFunc == nil {
+		return ""
+	}
+	return a.NameFunc()
+}
+
+func (a *ProviderAdapter) Description() string {
+	if a.DescriptionFunc == nil {
+		return ""
+	}
+	return a.DescriptionFunc()
+}
+
+func (a *ProviderAdapter) Bootstrap(projectName string, options map[string]string) error {
+	if a.BootstrapFunc == nil {
+		return nil
+	}
+	return a.BootstrapFunc(projectName, options)
+}
+
+func (a *ProviderAdapter) AvailableOptions() map[string]string {
+	if a.AvailableOptionsFunc == nil {
+		return nil
+	}
+	return a.AvailableOptionsFunc()
+}
+
+func (a *ProviderAdapter) CheckDependencies() error {
+	if a.CheckDependenciesFunc == nil {
+		return nil
+	}
+	return a.CheckDependenciesFunc()
+}
+
+func (a *ProviderAdapter) SupportedVersions() []string {
+	if a.SupportedVersionsFunc == nil {
+		return nil
+	}
+	return a.SupportedVersionsFunc()
+}
@@ -0,0 +1,148 @@
+package providers
+
+import (
+	"sort"
+	"strings"
+)
+
+// aliases maps alternative names to provider names
+var aliases = make(map[string]string)
+
+// Aliaser is implemented by providers that can also be referred to by
+// other names, e.g. "nextjs" for "next"
+type Aliaser interface {
+	Aliases() []string
+}
+
+// RegisterAlias makes alias refer to the provider called name. Aliases
+// never shadow a provider's own name.
+func RegisterAlias(alias, name string) {
+	aliases[strings.ToLower(alias)] = name
+}
+
+// UnregisterAlias removes alias
+func UnregisterAlias(alias string) {
+	delete(aliases, strings.ToLower(alias))
+}
+
+// AliasesFor returns the aliases of the provider called name, sorted
+func AliasesFor(name string) []string {
+	var names []string
+	for alias, target := range aliases {
+		if target == name && alias != name {
+			names = append(names, alias)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NotFoundError is returned by Get for an unknown provider name
+type NotFoundError struct {
+	Name string
+	// Suggestions are the closest provider names, best match first
+	Suggestions []string
+}
+
+func (e *NotFoundError) Error() string {
+	msg := "provider not found: " + e.Name
+	if len(e.Suggestions) > 0 {
+		msg += " (did you mean " + strings.Join(e.Suggestions, " or ") + "?)"
+	}
+	return msg
+}
+
+// maxSuggestions bounds the number of names suggested for a typo
+const maxSuggestions = 3
+
+// Suggest returns the provider names closest to name, matched against
+// provider names and aliases by edit distance or prefix
+func Suggest(name string) []string {
+	name = strings.ToLower(name)
+
+	type candidate struct {
+		name     string
+		distance int
+	}
+	best := make(map[string]int)
+	consider := func(candidateName, provider string) {
+		d := levenshtein(name, candidateName)
+		if d > suggestionDistance(name) && !strings.HasPrefix(candidateName, name) {
+			return
+		}
+		if prev, ok := best[provider]; !ok || d < prev {
+			best[provider] = d
+		}
+	}
+
+	for providerName := range Registry {
+		consider(strings.ToLower(providerName), providerName)
+	}
+	for alias, target := range aliases {
+		if _, ok := Registry[target]; ok {
+			consider(alias, target)
+		}
+	}
+
+	candidates := make([]candidate, 0, len(best))
+	for providerName, d := range best {
+		candidates = append(candidates, candidate{providerName, d})
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].distance != candidates[j].distance {
+			return candidates[i].distance < candidates[j].distance
+		}
+		return candidates[i].name < candidates[j].name
+	})
+
+	var names []string
+	for i, c := range candidates {
+		if i == maxSuggestions {
+			break
+		}
+		names = append(names, c.name)
+	}
+	return names
+}
+
+// suggestionDistance is the largest edit distance still considered a typo
+// of name: one edit for short names, two for longer ones
+func suggestionDistance(name string) int {
+	if len(name) <= 4 {
+		return 1
+	}
+	return 2
+}
+
+// levenshtein returns the edit distance between a and b
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = minInt(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+func minInt(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
@@ -0,0 +1,252 @@
+package providers
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sharik709/bootstraper/util"
+)
+
+// versionTimeout bounds how long a "--version" query may take
+const versionTimeout = 5 * time.Second
+
+// defaultVersionPattern extracts the first version-looking token from
+// "--version" output, e.g. "v20.1.0", "PHP 8.2.4 (cli)" or "go1.22.1"
+var defaultVersionPattern = regexp.MustCompile(`(\d+(?:\.\d+){0,2}(?:-[0-9A-Za-z.]+)?)`)
+
+// Dependency is a command a provider needs. In the registry it is either the
+// command name or an object with a version constraint:
+//
+//	{"command": "node", "version": ">=18.18", "version_args": ["--version"], "version_regex": "v(\\S+)"}
+type Dependency struct {
+	Command string `json:"command"`
+	// Version is a semver constraint such as ">=18" or "^8.1"
+	Version string `json:"version,omitempty"`
+	// VersionArgs are passed to Command to print its version; defaults to
+	// "--version", falling back to "version"
+	VersionArgs []string `json:"version_args,omitempty"`
+	// VersionRegex extracts the version from the output; its first group is
+	// used when it has one
+	VersionRegex string `json:"version_regex,omitempty"`
+}
+
+// UnmarshalJSON accepts both a plain command name and the object form
+func (d *Dependency) UnmarshalJSON(data []byte) error {
+	var command string
+	if err := json.Unmarshal(data, &command); err == nil {
+		*d = Dependency{Command: command}
+		return nil
+	}
+
+	type dependencyAlias Dependency
+	var raw dependencyAlias
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if raw.Command == "" {
+		return fmt.Errorf("dependency requires a command")
+	}
+	if raw.Version != "" {
+		if _, err := util.ParseConstraint(raw.Version); err != nil {
+			return err
+		}
+	}
+	if raw.VersionRegex != "" {
+		if _, err := regexp.Compile(raw.VersionRegex); err != nil {
+			return fmt.Errorf("invalid version_regex for %s: %v", raw.Command, err)
+		}
+	}
+
+	*d = Dependency(raw)
+	return nil
+}
+
+// DependencyStatus describes whether a dependency is installed
+type DependencyStatus struct {
+	Name      string `json:"name"`
+	Found     bool   `json:"found"`
+	Path      string `json:"path,omitempty"`
+	Version   string `json:"version,omitempty"`
+	Required  string `json:"required,omitempty"`
+	Satisfied bool   `json:"satisfied"`
+}
+
+// UnmetDependency describes a dependency that is missing or too old
+type UnmetDependency struct {
+	Command  string
+	Required string
+	// Found is the detected version, empty when the command is missing or
+	// its version could not be determined
+	Found   string
+	Missing bool
+}
+
+// DependencyError lists every unmet dependency of a provider
+type DependencyError struct {
+	Provider string
+	Unmet    []UnmetDependency
+}
+
+func (e *DependencyError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "unmet dependencies for %s:", e.Provider)
+	for _, dep := range e.Unmet {
+		switch {
+		case dep.Missing:
+			fmt.Fprintf(&b, "\n  - %s: not found", dep.Command)
+		case dep.Found == "":
+			fmt.Fprintf(&b, "\n  - %s: could not determine version, requires %s", dep.Command, dep.Required)
+		default:
+			fmt.Fprintf(&b, "\n  - %s: found %s, requires %s", dep.Command, dep.Found, dep.Required)
+		}
+	}
+	return b.String()
+}
+
+// DependencyReporter is implemented by providers that can report the
+// status of each of their dependencies
+type DependencyReporter interface {
+	DependencyStatuses() []DependencyStatus
+}
+
+// Dependencies returns the dependency status of p, or nil when p does not
+// report individual dependencies
+func Dependencies(p Provider) []DependencyStatus {
+	if dr, ok := p.(DependencyReporter); ok {
+		return dr.DependencyStatuses()
+	}
+	return nil
+}
+
+// DependencyStatuses resolves each dependency and queries its version
+func (p *ProviderDefinition) DependencyStatuses() []DependencyStatus {
+	statuses := make([]DependencyStatus, 0, len(p.DependsOn))
+	for _, dep := range p.DependsOn {
+		statuses = append(statuses, checkDependency(dep, true))
+	}
+	return statuses
+}
+
+// checkDependency looks up a command and checks its version constraint.
+// The version is only queried when there is a constraint or queryVersion is set.
+func checkDependency(dep Dependency, queryVersion bool) DependencyStatus {
+	status := DependencyStatus{Name: dep.Command, Required: dep.Version}
+
+	path, err := util.CommandPath(dep.Command)
+	if err != nil {
+		return status
+	}
+	status.Found = true
+	status.Path = path
+
+	if dep.Version == "" && !queryVersion {
+		status.Satisfied = true
+		return status
+	}
+
+	output := commandVersion(path, dep.VersionArgs)
+	status.Version = extractVersion(output, dep.VersionRegex)
+	if dep.Version == "" {
+		status.Satisfied = true
+		if status.Version == "" {
+			status.Version = firstLine(output)
+		}
+		return status
+	}
+
+	constraint, err := util.ParseConstraint(dep.Version)
+	if err != nil {
+		return status
+	}
+	if v, err := util.ParseVersion(status.Version); err == nil {
+		status.Satisfied = constraint.Check(v)
+	}
+	return status
+}
+
+// checkDependencies returns a *DependencyError when a dependency is missing
+// or does not satisfy its version constraint
+func checkDependencies(provider string, deps []Dependency) error {
+	var unmet []UnmetDependency
+	for _, dep := range deps {
+		status := checkDependency(dep, false)
+		if status.Satisfied {
+			continue
+		}
+		unmet = append(unmet, UnmetDependency{
+			Command:  dep.Command,
+			Required: dep.Version,
+			Found:    status.Version,
+			Missing:  !status.Found,
+		})
+	}
+
+	if len(unmet) > 0 {
+		return &DependencyError{Provider: provider, Unmet: unmet}
+	}
+	return nil
+}
+
+// extractVersion finds the version in command output using pattern, or the
+// default pattern when it is empty
+func extractVersion(output, pattern string) string {
+	re := defaultVersionPattern
+	if pattern != "" {
+		var err error
+		if re, err = regexp.Compile(pattern); err != nil {
+			return ""
+		}
+	}
+
+	m := re.FindStringSubmatch(output)
+	switch {
+	case m == nil:
+		return ""
+	case len(m) > 1 && m[1] != "":
+		return m[1]
+	}
+	return m[0]
+}
+
+// versionCache avoids querying the same binary repeatedly, since several
+// providers usually share a dependency such as npx
+var versionCache sync.Map
+
+// commandVersion returns the output of "<path> <args>". Without args it
+// tries "--version", then "version" for tools such as go that use a
+// subcommand instead.
+func commandVersion(path string, args []string) string {
+	key := path + "\x00" + strings.Join(args, "\x00")
+	if cached, ok := versionCache.Load(key); ok {
+		return cached.(string)
+	}
+
+	candidates := [][]string{args}
+	if len(args) == 0 {
+		candidates = [][]string{{"--version"}, {"version"}}
+	}
+
+	output := ""
+	for _, candidate := range candidates {
+		if out, err := util.CommandOutput(versionTimeout, path, candidate...); err == nil {
+			output = out
+			break
+		}
+	}
+
+	versionCache.Store(key, output)
+	return output
+}
+
+func firstLine(s string) string {
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
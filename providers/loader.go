@@ -0,0 +1,208 @@
+package providers
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/sharik709/bootstraper/util"
+)
+
+// embeddedRegistry is the built-in provider registry shipped with the binary
+//
+//go:embed registry.json
+var embeddedRegistry []byte
+
+// ProjectOverlayDir is the project-local directory holding provider overlays
+const ProjectOverlayDir = ".bootstraper/providers"
+
+// parseRegistry decodes a provider registry document
+func parseRegistry(data []byte) (*ProviderRegistry, error) {
+	var registry ProviderRegistry
+	if err := json.Unmarshal(data, &registry); err != nil {
+		return nil, err
+	}
+	return &registry, nil
+}
+
+// loadOverlayDir reads every *.json file in dir, in lexical order, and returns
+// the provider definitions they contain. A missing directory is not an error.
+// Files that cannot be read or parsed are skipped and reported in the error,
+// which is returned alongside the definitions from the other files.
+func loadOverlayDir(dir string) ([]ProviderDefinition, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(matches)
+
+	var definitions []ProviderDefinition
+	var errs []error
+	for _, path := range matches {
+		defs, err := loadOverlayFile(path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("skipping provider overlay %s: %v", path, err))
+			continue
+		}
+		definitions = append(definitions, defs...)
+	}
+
+	return definitions, errors.Join(errs...)
+}
+
+// loadOverlayFile reads the provider definitions in one overlay file
+func loadOverlayFile(path string) ([]ProviderDefinition, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	registry, err := parseRegistry(data)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, def := range registry.Providers {
+		if strings.TrimSpace(def.ProviderName) == "" {
+			return nil, errors.New("contains a provider without a name")
+		}
+	}
+	return registry.Providers, nil
+}
+
+// mergeDefinitions layers overlay on top of base. A definition in overlay
+// replaces the base definition with the same name; new names are appended.
+func mergeDefinitions(base, overlay []ProviderDefinition) []ProviderDefinition {
+	index := make(map[string]int, len(base))
+	merged := make([]ProviderDefinition, len(base))
+	copy(merged, base)
+	for i, def := range merged {
+		index[def.ProviderName] = i
+	}
+
+	for _, def := range overlay {
+		if i, ok := index[def.ProviderName]; ok {
+			merged[i] = def
+			continue
+		}
+		index[def.ProviderName] = len(merged)
+		merged = append(merged, def)
+	}
+
+	return merged
+}
+
+// overlayDirs returns the overlay directories in increasing order of precedence:
+// the user directory (~/.bootstraper/providers.d) and then the project
+// directory (./.bootstraper/providers).
+func overlayDirs() []string {
+	var dirs []string
+	if dir, err := util.GetAppDir(); err == nil {
+		dirs = append(dirs, filepath.Join(dir, "providers.d"))
+	}
+	dirs = append(dirs, ProjectOverlayDir)
+	return dirs
+}
+
+// baseRegistry returns the registry that overlays are applied to: the cached
+// remote registry from cacheDir when it is valid and at least as recent as
+// the embedded one, otherwise the embedded registry.
+func baseRegistry(cacheDir string) (*ProviderRegistry, error) {
+	registry, err := parseRegistry(embeddedRegistry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse built-in provider registry: %v", err)
+	}
+
+	if cacheDir == "" {
+		return registry, nil
+	}
+
+	cached, err := loadCachedRegistry(cacheDir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			fmt.Fprintf(os.Stderr, "Warning: Ignoring cached provider registry: %v\n", err)
+		}
+		return registry, nil
+	}
+
+	// Dates in updated_at are ISO 8601 so they compare lexically
+	if cached.UpdatedAt < registry.UpdatedAt {
+		return registry, nil
+	}
+	return cached, nil
+}
+
+// loadDefinitions builds the provider definitions from the base registry and
+// the given overlay directories, later directories taking precedence. Broken
+// overlays are skipped: the definitions are still returned, along with an
+// error describing what was skipped.
+func loadDefinitions(cacheDir string, dirs ...string) ([]ProviderDefinition, error) {
+	registry, err := baseRegistry(cacheDir)
+	if err != nil {
+		return nil, err
+	}
+
+	definitions := registry.Providers
+	var errs []error
+	for _, dir := range dirs {
+		overlay, err := loadOverlayDir(dir)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		definitions = mergeDefinitions(definitions, overlay)
+	}
+
+	return definitions, errors.Join(errs...)
+}
+
+func loadProviders() ([]Provider, error) {
+	// A broken config file should not prevent providers from loading; the
+	// defaults are returned alongside the error in that case
+	config, _ := util.LoadConfig()
+
+	definitions, err := loadDefinitions(config.CacheDir, overlayDirs()...)
+
+	providers := make([]Provider, 0, len(definitions))
+	for _, providerDef := range definitions {
+		provider := providerDef // Create local copy to avoid referencing loop variable
+		providers = append(providers, &provider)
+	}
+
+	return providers, err
+}
+
+func init() {
+	// Load providers from the embedded registry and any overlays. Overlays
+	// that fail to load are reported and skipped, the rest still register.
+	jsonProviders, err := loadProviders()
+	if err != nil {
+		for _, line := range strings.Split(err.Error(), "\n") {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", line)
+		}
+	}
+
+	for _, provider := range jsonProviders {
+		Register(provider)
+	}
+
+	RegisterConfigAliases()
+}
+
+// RegisterConfigAliases registers the aliases from the user configuration,
+// e.g. {"svc": "internal-service"}, and returns their names
+func RegisterConfigAliases() []string {
+	config, _ := util.LoadConfig()
+
+	names := make([]string, 0, len(config.Aliases))
+	for alias, name := range config.Aliases {
+		RegisterAlias(alias, name)
+		names = append(names, alias)
+	}
+	sort.Strings(names)
+	return names
+}
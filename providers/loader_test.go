@@ -0,0 +1,94 @@
+package providers
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeOverlay(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func findDefinition(defs []ProviderDefinition, name string) *ProviderDefinition {
+	for i := range defs {
+		if defs[i].ProviderName == name {
+			return &defs[i]
+		}
+	}
+	return nil
+}
+
+func TestLoadDefinitions(t *testing.T) {
+	t.Run("Embedded registry is loaded without overlays", func(t *testing.T) {
+		defs, err := loadDefinitions("")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if findDefinition(defs, "next") == nil {
+			t.Errorf("Expected embedded registry to contain 'next'")
+		}
+	})
+
+	t.Run("Overlays replace and extend by name", func(t *testing.T) {
+		userDir := filepath.Join(t.TempDir(), "providers.d")
+		projectDir := filepath.Join(t.TempDir(), "providers")
+
+		writeOverlay(t, userDir, "internal.json", `{"providers": [
+			{"name": "svc", "description": "User service", "command": "svc-gen"},
+			{"name": "next", "description": "User next", "command": "npx"}
+		]}`)
+		writeOverlay(t, projectDir, "svc.json", `{"providers": [
+			{"name": "svc", "description": "Project service", "command": "svc-gen"}
+		]}`)
+
+		defs, err := loadDefinitions("", userDir, projectDir)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if def := findDefinition(defs, "next"); def == nil || def.ProviderDesc != "User next" {
+			t.Errorf("Expected user overlay to replace 'next'")
+		}
+
+		if def := findDefinition(defs, "svc"); def == nil || def.ProviderDesc != "Project service" {
+			t.Errorf("Expected project overlay to take precedence for 'svc'")
+		}
+	})
+
+	t.Run("Invalid overlay is reported", func(t *testing.T) {
+		dir := t.TempDir()
+		writeOverlay(t, dir, "broken.json", `{"providers": [`)
+
+		if _, err := loadDefinitions("", dir); err == nil {
+			t.Errorf("Expected error for malformed overlay, got nil")
+		}
+	})
+
+	t.Run("Invalid overlays are skipped", func(t *testing.T) {
+		userDir := t.TempDir()
+		projectDir := t.TempDir()
+		writeOverlay(t, userDir, "a-broken.json", `{bad`)
+		writeOverlay(t, userDir, "b-svc.json", `{"providers": [{"name": "svc", "command": "svc-gen"}]}`)
+		writeOverlay(t, projectDir, "unnamed.json", `{"providers": [{"command": "x"}]}`)
+
+		defs, err := loadDefinitions("", userDir, projectDir)
+		if err == nil || !strings.Contains(err.Error(), "a-broken.json") || !strings.Contains(err.Error(), "unnamed.json") {
+			t.Errorf("Expected error naming both broken overlays, got %v", err)
+		}
+		if findDefinition(defs, "next") == nil {
+			t.Errorf("Expected embedded providers to still load")
+		}
+		if findDefinition(defs, "svc") == nil {
+			t.Errorf("Expected valid overlays to still load")
+		}
+	})
+}
@@ -0,0 +1,228 @@
+package providers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/sharik709/bootstraper/util"
+)
+
+// OptionType is the value type of a provider option
+type OptionType string
+
+const (
+	// OptionString accepts any value
+	OptionString OptionType = "string"
+
+	// OptionBool accepts true/false (and yes/no, y/n, 1/0)
+	OptionBool OptionType = "bool"
+
+	// OptionEnum accepts exactly one of the option's Values
+	OptionEnum OptionType = "enum"
+
+	// OptionInt accepts an integer
+	OptionInt OptionType = "int"
+
+	// OptionList accepts a comma-separated list, restricted to Values if set
+	OptionList OptionType = "list"
+)
+
+// Option describes a single provider option. In the registry an option is
+// either a plain description string (a free-form string option) or an object:
+//
+//	"style": {"type": "enum", "values": ["css", "scss"], "default": "css", "help": "..."}
+type Option struct {
+	Name     string      `json:"-"`
+	Type     OptionType  `json:"type,omitempty"`
+	Help     string      `json:"help,omitempty"`
+	Values   []string    `json:"values,omitempty"`
+	Default  string      `json:"default,omitempty"`
+	Required bool        `json:"required,omitempty"`
+	Render   *RenderSpec `json:"render,omitempty"`
+}
+
+// UnmarshalJSON accepts both the legacy description string and the object form.
+// Defaults may be given as any JSON scalar.
+func (o *Option) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '"' {
+		var help string
+		if err := json.Unmarshal(data, &help); err != nil {
+			return err
+		}
+		*o = Option{Type: OptionString, Help: help}
+		return nil
+	}
+
+	type optionAlias Option
+	var raw struct {
+		optionAlias
+		Default interface{} `json:"default,omitempty"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*o = Option(raw.optionAlias)
+	if raw.Default != nil {
+		o.Default = util.FormatValue(raw.Default)
+	}
+	if o.Type == "" {
+		o.Type = OptionString
+	}
+
+	switch o.Type {
+	case OptionString, OptionBool, OptionInt, OptionList:
+	case OptionEnum:
+		if len(o.Values) == 0 {
+			return fmt.Errorf("enum option requires values")
+		}
+	default:
+		return fmt.Errorf("unknown option type: %s", o.Type)
+	}
+
+	return nil
+}
+
+// Usage returns the help text with the allowed values and default appended
+func (o Option) Usage() string {
+	usage := o.Help
+	if len(o.Values) > 0 {
+		usage += " (" + strings.Join(o.Values, ", ") + ")"
+	}
+	if o.Default != "" {
+		usage += " [default: " + o.Default + "]"
+	}
+	if o.Required {
+		usage += " (required)"
+	}
+	return strings.TrimSpace(usage)
+}
+
+// Normalize validates value against the option and returns its canonical form
+func (o Option) Normalize(value string) (string, error) {
+	switch o.Type {
+	case OptionBool:
+		switch strings.ToLower(strings.TrimSpace(value)) {
+		case "true", "yes", "y", "1", "on":
+			return "true", nil
+		case "false", "no", "n", "0", "off":
+			return "false", nil
+		}
+		return "", fmt.Errorf("invalid value %q for --%s: expected true or false", value, o.Name)
+	case OptionInt:
+		n, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return "", fmt.Errorf("invalid value %q for --%s: expected an integer", value, o.Name)
+		}
+		return strconv.Itoa(n), nil
+	case OptionEnum:
+		if !containsString(o.Values, value) {
+			return "", fmt.Errorf("invalid value %q for --%s: must be one of %s", value, o.Name, strings.Join(o.Values, ", "))
+		}
+		return value, nil
+	case OptionList:
+		items := splitList(value)
+		if len(o.Values) > 0 {
+			for _, item := range items {
+				if !containsString(o.Values, item) {
+					return "", fmt.Errorf("invalid value %q for --%s: items must be from %s", item, o.Name, strings.Join(o.Values, ", "))
+				}
+			}
+		}
+		return strings.Join(items, ","), nil
+	}
+	return value, nil
+}
+
+// OptionSchemaProvider is implemented by providers that describe their
+// options with a typed schema rather than plain descriptions
+type OptionSchemaProvider interface {
+	OptionSchema() []Option
+}
+
+// Schema returns the options of p sorted by name. Providers that do not
+// implement OptionSchemaProvider get a string option per AvailableOptions entry.
+func Schema(p Provider) []Option {
+	if sp, ok := p.(OptionSchemaProvider); ok {
+		return sp.OptionSchema()
+	}
+
+	options := make([]Option, 0, len(p.AvailableOptions()))
+	for name, help := range p.AvailableOptions() {
+		options = append(options, Option{Name: name, Type: OptionString, Help: help})
+	}
+	sortOptions(options)
+	return options
+}
+
+// ValidateOptions checks values against schema and returns a normalized copy.
+// Unknown options, invalid values and missing required options are errors.
+func ValidateOptions(schema []Option, values map[string]string) (map[string]string, error) {
+	byName := make(map[string]Option, len(schema))
+	for _, opt := range schema {
+		byName[opt.Name] = opt
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	normalized := make(map[string]string, len(values))
+	for _, name := range names {
+		opt, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown option: --%s", name)
+		}
+
+		value, err := opt.Normalize(values[name])
+		if err != nil {
+			return nil, err
+		}
+		normalized[name] = value
+	}
+
+	for _, opt := range schema {
+		if _, ok := normalized[opt.Name]; !ok && opt.Required {
+			return nil, fmt.Errorf("missing required option: --%s", opt.Name)
+		}
+	}
+
+	return normalized, nil
+}
+
+func sortOptions(options []Option) {
+	sort.Slice(options, func(i, j int) bool {
+		return options[i].Name < options[j].Name
+	})
+}
+
+func splitList(value string) []string {
+	return splitOn(value, ",")
+}
+
+// splitOn splits value on sep, trimming the items and dropping empty ones
+func splitOn(value, sep string) []string {
+	var items []string
+	for _, item := range strings.Split(value, sep) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
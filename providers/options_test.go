@@ -0,0 +1,83 @@
+package providers
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestOptionSchema(t *testing.T) {
+	t.Run("Legacy and typed options decode", func(t *testing.T) {
+		var def ProviderDefinition
+		err := json.Unmarshal([]byte(`{
+			"name": "demo",
+			"options": {
+				"legacy": "A plain description",
+				"typescript": {"type": "bool", "default": true, "help": "Use TypeScript"},
+				"style": {"type": "enum", "values": ["css", "scss"], "required": true}
+			}
+		}`), &def)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		schema := def.OptionSchema()
+		if len(schema) != 3 || schema[0].Name != "legacy" || schema[2].Name != "typescript" {
+			t.Fatalf("Expected options sorted by name, got %+v", schema)
+		}
+		if schema[0].Type != OptionString || schema[0].Help != "A plain description" {
+			t.Errorf("Expected legacy option to be a string option, got %+v", schema[0])
+		}
+		if schema[2].Default != "true" {
+			t.Errorf("Expected bool default 'true', got %q", schema[2].Default)
+		}
+	})
+
+	t.Run("Enum without values is rejected", func(t *testing.T) {
+		var opt Option
+		if err := json.Unmarshal([]byte(`{"type": "enum"}`), &opt); err == nil {
+			t.Errorf("Expected error, got nil")
+		}
+	})
+
+	t.Run("Values are validated and normalized", func(t *testing.T) {
+		schema := []Option{
+			{Name: "ts", Type: OptionBool},
+			{Name: "style", Type: OptionEnum, Values: []string{"css", "scss"}},
+			{Name: "port", Type: OptionInt},
+			{Name: "platforms", Type: OptionList, Values: []string{"ios", "web"}},
+		}
+
+		values, err := ValidateOptions(schema, map[string]string{
+			"ts":        "yes",
+			"style":     "scss",
+			"port":      "8080",
+			"platforms": "ios, web",
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if values["ts"] != "true" || values["platforms"] != "ios,web" {
+			t.Errorf("Values not normalized as expected: %v", values)
+		}
+
+		invalid := []map[string]string{
+			{"style": "foo"},
+			{"ts": "maybe"},
+			{"port": "eighty"},
+			{"platforms": "ios,android"},
+			{"unknown": "x"},
+		}
+		for _, input := range invalid {
+			if _, err := ValidateOptions(schema, input); err == nil {
+				t.Errorf("Expected error for %v, got nil", input)
+			}
+		}
+	})
+
+	t.Run("Required options must be set", func(t *testing.T) {
+		schema := []Option{{Name: "org", Type: OptionString, Required: true}}
+		if _, err := ValidateOptions(schema, map[string]string{}); err == nil {
+			t.Errorf("Expected error for missing required option, got nil")
+		}
+	})
+}
@@ -0,0 +1,45 @@
+package providers
+
+import (
+	"fmt"
+)
+
+const (
+	// PassthroughEnd appends pass-through args after the rendered options
+	PassthroughEnd = "end"
+
+	// PassthroughBeforeOptions inserts pass-through args between the
+	// provider's args and the rendered options
+	PassthroughBeforeOptions = "before-options"
+)
+
+// PassthroughSpec declares whether a provider accepts raw generator
+// arguments given after "--" on the command line, and where they go
+type PassthroughSpec struct {
+	Allowed bool `json:"allowed"`
+	// Placement is PassthroughEnd (default) or PassthroughBeforeOptions
+	Placement string `json:"placement,omitempty"`
+}
+
+// placeArgs combines the provider args, the rendered option args and the
+// pass-through args according to spec
+func placeArgs(provider string, spec *PassthroughSpec, args, optionArgs, passthrough []string) ([]string, error) {
+	if len(passthrough) == 0 {
+		return append(args, optionArgs...), nil
+	}
+
+	if spec == nil || !spec.Allowed {
+		return nil, fmt.Errorf("%s does not accept pass-through arguments", provider)
+	}
+
+	switch spec.Placement {
+	case "", PassthroughEnd:
+		args = append(args, optionArgs...)
+		return append(args, passthrough...), nil
+	case PassthroughBeforeOptions:
+		args = append(args, passthrough...)
+		return append(args, optionArgs...), nil
+	}
+
+	return nil, fmt.Errorf("%s: unknown pass-through placement %q", provider, spec.Placement)
+}
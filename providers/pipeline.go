@@ -0,0 +1,193 @@
+package providers
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Step is a single command in a provider pipeline. Providers declare steps
+// in "pre" and "post" around their main command; each string field is a
+// template (see TemplateData).
+type Step struct {
+	// Name describes the step in progress output and errors
+	Name    string            `json:"name,omitempty"`
+	Command string            `json:"command,omitempty"`
+	Args    []string          `json:"args,omitempty"`
+	Dir     string            `json:"dir,omitempty"`
+	Env     map[string]string `json:"env,omitempty"`
+	// When skips the step unless it renders to a true value, e.g. `{{has "git"}}`
+	When string `json:"when,omitempty"`
+	// Builtin runs an internal action instead of Command: "mkdir" creates
+	// each arg as a directory, "write" writes Content to the file in args[0]
+	Builtin string `json:"builtin,omitempty"`
+	Content string `json:"content,omitempty"`
+}
+
+const (
+	// BuiltinMkdir creates directories
+	BuiltinMkdir = "mkdir"
+
+	// BuiltinWrite writes a new file
+	BuiltinWrite = "write"
+)
+
+// Planner is implemented by providers that can describe the commands
+// Bootstrap would run without running them
+type Planner interface {
+	Plan(project Project, options map[string]string) ([]Invocation, error)
+}
+
+// Invocation is a fully rendered step, ready to run
+type Invocation struct {
+	Step    string   `json:"step"`
+	Command string   `json:"command,omitempty"`
+	Args    []string `json:"args,omitempty"`
+	Dir     string   `json:"dir,omitempty"`
+	Env     []string `json:"env,omitempty"`
+	Builtin string   `json:"builtin,omitempty"`
+	Content string   `json:"content,omitempty"`
+}
+
+// String renders the invocation as a shell-like command line
+func (inv Invocation) String() string {
+	if inv.Builtin != "" {
+		return "[" + inv.Builtin + "] " + strings.Join(quoteArgs(inv.Args), " ")
+	}
+	return strings.Join(quoteArgs(append([]string{inv.Command}, inv.Args...)), " ")
+}
+
+// renderStep renders step with data. The second return value is false when
+// the step's condition does not hold.
+func renderStep(step Step, data TemplateData) (Invocation, bool, error) {
+	if step.When != "" {
+		cond, err := RenderTemplate(step.When, data)
+		if err != nil {
+			return Invocation{}, false, err
+		}
+		if !isTruthy(cond) {
+			return Invocation{}, false, nil
+		}
+	}
+
+	inv := Invocation{Step: step.Name, Builtin: step.Builtin}
+
+	var err error
+	if inv.Command, err = RenderTemplate(step.Command, data); err != nil {
+		return Invocation{}, false, err
+	}
+	if inv.Args, err = renderArgs(step.Args, data); err != nil {
+		return Invocation{}, false, err
+	}
+	if inv.Dir, err = RenderTemplate(step.Dir, data); err != nil {
+		return Invocation{}, false, err
+	}
+	if inv.Content, err = RenderTemplate(step.Content, data); err != nil {
+		return Invocation{}, false, err
+	}
+	for _, name := range sortedKeys(step.Env) {
+		value, err := RenderTemplate(step.Env[name], data)
+		if err != nil {
+			return Invocation{}, false, err
+		}
+		inv.Env = append(inv.Env, name+"="+value)
+	}
+
+	switch inv.Builtin {
+	case "":
+		if inv.Command == "" {
+			return Invocation{}, false, fmt.Errorf("step %q has no command", step.Name)
+		}
+	case BuiltinMkdir:
+	case BuiltinWrite:
+		if len(inv.Args) != 1 {
+			return Invocation{}, false, fmt.Errorf("step %q: write expects exactly one path", step.Name)
+		}
+	default:
+		return Invocation{}, false, fmt.Errorf("step %q: unknown builtin %q", step.Name, inv.Builtin)
+	}
+
+	return inv, true, nil
+}
+
+// RunPlan runs the invocations in order and stops at the first failure
+func RunPlan(plan []Invocation) error {
+	for _, inv := range plan {
+		if err := runInvocation(inv); err != nil {
+			if inv.Step != "" {
+				return fmt.Errorf("step %q failed: %v", inv.Step, err)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func runInvocation(inv Invocation) error {
+	switch inv.Builtin {
+	case BuiltinMkdir:
+		for _, dir := range inv.Args {
+			if err := os.MkdirAll(resolvePath(inv.Dir, dir), 0755); err != nil {
+				return err
+			}
+		}
+		return nil
+	case BuiltinWrite:
+		path := resolvePath(inv.Dir, inv.Args[0])
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		// Never overwrite files created by the generator
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err != nil {
+			return err
+		}
+		if _, err := f.WriteString(inv.Content); err != nil {
+			f.Close()
+			return err
+		}
+		return f.Close()
+	}
+
+	cmd := exec.Command(inv.Command, inv.Args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Stdin = os.Stdin
+	cmd.Dir = inv.Dir
+	if len(inv.Env) > 0 {
+		cmd.Env = append(os.Environ(), inv.Env...)
+	}
+	return cmd.Run()
+}
+
+// resolvePath resolves path relative to dir unless it is absolute
+func resolvePath(dir, path string) string {
+	if filepath.IsAbs(path) || dir == "" {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
+
+// isTruthy reports whether a rendered condition counts as true
+func isTruthy(s string) bool {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "false", "0", "no":
+		return false
+	}
+	return true
+}
+
+// quoteArgs quotes args containing whitespace or shell metacharacters
+func quoteArgs(args []string) []string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if arg == "" || strings.ContainsAny(arg, " \t\n\"'\\$`|&;<>()*?[]{}#~") {
+			quoted[i] = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+		} else {
+			quoted[i] = arg
+		}
+	}
+	return quoted
+}
@@ -0,0 +1,138 @@
+package providers
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestPipeline(t *testing.T) {
+	def := &ProviderDefinition{
+		ProviderName: "demo",
+		Command:      "demo-gen",
+		CommandArgs:  []string{"{{.Name}}"},
+		Dir:          "{{.Name}}",
+		Options: map[string]Option{
+			"git": {Type: OptionBool, Render: &RenderSpec{Skip: true}},
+		},
+		Pre: []Step{
+			{Name: "mkdir", Builtin: BuiltinMkdir, Args: []string{"{{.Name}}"}},
+		},
+		Post: []Step{
+			{Name: "git", Command: "git", Args: []string{"init"}, Dir: "{{.Name}}", When: `{{has "git"}}`},
+			{Name: "readme", Builtin: BuiltinWrite, Args: []string{"README.md"}, Dir: "{{.Name}}", Content: "# {{.Name}}\n"},
+		},
+	}
+
+	t.Run("Plan orders steps and evaluates conditions", func(t *testing.T) {
+		plan, err := def.Plan(Project{Name: "app"}, map[string]string{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		var steps []string
+		for _, inv := range plan {
+			steps = append(steps, inv.Step)
+		}
+		if !reflect.DeepEqual(steps, []string{"mkdir", "main", "readme"}) {
+			t.Errorf("Expected steps [mkdir main readme], got %v", steps)
+		}
+		if plan[1].Dir != "app" || !reflect.DeepEqual(plan[1].Args, []string{"app"}) {
+			t.Errorf("Main step not rendered as expected: %+v", plan[1])
+		}
+
+		plan, err = def.Plan(Project{Name: "app"}, map[string]string{"git": "true"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(plan) != 4 || plan[2].Step != "git" {
+			t.Errorf("Expected git step to be included, got %+v", plan)
+		}
+	})
+
+	t.Run("Step directories are resolved against the project directory", func(t *testing.T) {
+		base := filepath.Join(string(filepath.Separator), "work")
+		plan, err := def.Plan(Project{Name: "app", Dir: base}, map[string]string{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if plan[0].Dir != base {
+			t.Errorf("Expected pre step to run in %s, got %s", base, plan[0].Dir)
+		}
+		if plan[1].Dir != filepath.Join(base, "app") {
+			t.Errorf("Expected main step to run in %s, got %s", filepath.Join(base, "app"), plan[1].Dir)
+		}
+	})
+
+	t.Run("Pass-through args follow the placement", func(t *testing.T) {
+		def := &ProviderDefinition{
+			ProviderName: "demo",
+			Command:      "demo-gen",
+			CommandArgs:  []string{"{{.Name}}"},
+			Options: map[string]Option{
+				"ts": {Type: OptionBool},
+			},
+		}
+		project := Project{Name: "app", Args: []string{"--use-pnpm", "--import-alias", "@/*"}}
+		options := map[string]string{"ts": "true"}
+
+		if _, err := def.Plan(project, options); err == nil {
+			t.Error("Expected error when pass-through is not allowed, got nil")
+		}
+
+		cases := []struct {
+			placement string
+			expected  []string
+		}{
+			{"", []string{"app", "--ts", "--use-pnpm", "--import-alias", "@/*"}},
+			{PassthroughBeforeOptions, []string{"app", "--use-pnpm", "--import-alias", "@/*", "--ts"}},
+		}
+		for _, c := range cases {
+			def.Passthrough = &PassthroughSpec{Allowed: true, Placement: c.placement}
+			plan, err := def.Plan(project, options)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(plan[0].Args, c.expected) {
+				t.Errorf("Expected args %v for placement %q, got %v", c.expected, c.placement, plan[0].Args)
+			}
+		}
+
+		def.Passthrough = &PassthroughSpec{Allowed: true, Placement: "middle"}
+		if _, err := def.Plan(project, options); err == nil {
+			t.Error("Expected error for unknown placement, got nil")
+		}
+	})
+
+	t.Run("Run stops at the first failure", func(t *testing.T) {
+		dir := t.TempDir()
+		plan := []Invocation{
+			{Step: "mkdir", Builtin: BuiltinMkdir, Dir: dir, Args: []string{"app"}},
+			{Step: "broken", Command: "bt-command-that-does-not-exist", Dir: dir},
+			{Step: "readme", Builtin: BuiltinWrite, Dir: dir, Args: []string{"app/README.md"}},
+		}
+
+		if err := RunPlan(plan); err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+		if _, err := os.Stat(filepath.Join(dir, "app")); err != nil {
+			t.Errorf("Expected first step to run: %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(dir, "app", "README.md")); !os.IsNotExist(err) {
+			t.Errorf("Expected steps after the failure to be skipped")
+		}
+	})
+
+	t.Run("Write never overwrites", func(t *testing.T) {
+		dir := t.TempDir()
+		inv := Invocation{Step: "write", Builtin: BuiltinWrite, Dir: dir, Args: []string{"main.go"}, Content: "package main\n"}
+		if err := RunPlan([]Invocation{inv}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if err := RunPlan([]Invocation{inv}); err == nil {
+			t.Errorf("Expected error when the file exists, got nil")
+		}
+	})
+}
@@ -0,0 +1,188 @@
+package providers
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/sharik709/bootstraper/util"
+)
+
+// TemplateData is the data available to templates in registry args, env
+// values and paths, e.g. "create-next-app@{{default \"latest\" .Version}}"
+type TemplateData struct {
+	// Name is the project name
+	Name string
+	// Dir is the directory the project is created in
+	Dir string
+	// Path is the project's own directory
+	Path string
+	// Version is the requested framework version, empty for the latest one
+	Version string
+	// Options holds the validated option values
+	Options map[string]string
+	// Config exposes user configuration values (projectDir, cacheDir)
+	Config map[string]string
+}
+
+// NewTemplateData returns the template data for a project
+func NewTemplateData(project Project, options map[string]string) TemplateData {
+	config, _ := util.LoadConfig()
+	if options == nil {
+		options = make(map[string]string)
+	}
+
+	return TemplateData{
+		Name:    project.Name,
+		Dir:     project.Dir,
+		Path:    project.Path(),
+		Version: options["version"],
+		Options: options,
+		Config: map[string]string{
+			"projectDir": config.ProjectDir,
+			"cacheDir":   config.CacheDir,
+		},
+	}
+}
+
+// legacyPlaceholders maps the pre-template placeholders to their template form
+var legacyPlaceholders = strings.NewReplacer(
+	"{project-name}", "{{.Name}}",
+	"@{version}", `@{{default "latest" .Version}}`,
+	"{version}", "{{.Version}}",
+	"{module}", `{{default (printf "github.com/example/%s" .Name) (opt "module")}}`,
+)
+
+// RenderTemplate renders text with data. Strings without "{{" are first
+// translated from the legacy {project-name}/{version}/{module} placeholders.
+func RenderTemplate(text string, data TemplateData) (string, error) {
+	if !strings.Contains(text, "{{") {
+		text = legacyPlaceholders.Replace(text)
+		if !strings.Contains(text, "{{") {
+			return text, nil
+		}
+	}
+
+	tmpl, err := template.New("arg").Option("missingkey=zero").Funcs(templateFuncs(data)).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid template %q: %v", text, err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render %q: %v", text, err)
+	}
+
+	return buf.String(), nil
+}
+
+// renderArgs renders each arg, dropping args that render to an empty string
+// so conditional args can be expressed as "{{if opt \"x\"}}--x{{end}}"
+func renderArgs(args []string, data TemplateData) ([]string, error) {
+	rendered := make([]string, 0, len(args))
+	for _, arg := range args {
+		value, err := RenderTemplate(arg, data)
+		if err != nil {
+			return nil, err
+		}
+		if value != "" {
+			rendered = append(rendered, value)
+		}
+	}
+	return rendered, nil
+}
+
+// templateFuncs returns the helper functions available to registry templates
+func templateFuncs(data TemplateData) template.FuncMap {
+	funcs := TextFuncs()
+	// opt returns an option value, empty when unset
+	funcs["opt"] = func(name string) string { return data.Options[name] }
+	// has reports whether an option was set to a non-empty, non-false value
+	funcs["has"] = func(name string) bool {
+		value := data.Options[name]
+		return value != "" && value != "false"
+	}
+	return funcs
+}
+
+// TextFuncs returns the text helpers shared by registry and project
+// templates: default, ternary, case conversions and string functions
+func TextFuncs() template.FuncMap {
+	return template.FuncMap{
+		"default": func(def string, value interface{}) string {
+			if s := fmt.Sprint(value); value != nil && s != "" {
+				return s
+			}
+			return def
+		},
+		"ternary": func(yes, no interface{}, cond bool) interface{} {
+			if cond {
+				return yes
+			}
+			return no
+		},
+		"lower":    strings.ToLower,
+		"upper":    strings.ToUpper,
+		"trim":     strings.TrimSpace,
+		"replace":  func(old, new, s string) string { return strings.ReplaceAll(s, old, new) },
+		"contains": func(substr, s string) bool { return strings.Contains(s, substr) },
+		"split":    func(sep, s string) []string { return splitOn(s, sep) },
+		"join":     func(sep string, items []string) string { return strings.Join(items, sep) },
+		"kebab":    func(s string) string { return strings.Join(words(s), "-") },
+		"snake":    func(s string) string { return strings.Join(words(s), "_") },
+		"pascal":   pascalCase,
+		"camel": func(s string) string {
+			p := []rune(pascalCase(s))
+			if len(p) > 0 {
+				p[0] = unicode.ToLower(p[0])
+			}
+			return string(p)
+		},
+	}
+}
+
+// words splits s into lower-case words on separators and case changes,
+// e.g. "myAPIServer_v2" -> ["my", "api", "server", "v2"]
+func words(s string) []string {
+	var result []string
+	var current []rune
+	runes := []rune(s)
+
+	flush := func() {
+		if len(current) > 0 {
+			result = append(result, strings.ToLower(string(current)))
+			current = current[:0]
+		}
+	}
+
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			flush()
+			continue
+		}
+
+		if unicode.IsUpper(r) && len(current) > 0 {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				flush()
+			}
+		}
+		current = append(current, r)
+	}
+	flush()
+
+	return result
+}
+
+func pascalCase(s string) string {
+	var b strings.Builder
+	for _, word := range words(s) {
+		r := []rune(word)
+		r[0] = unicode.ToUpper(r[0])
+		b.WriteString(string(r))
+	}
+	return b.String()
+}
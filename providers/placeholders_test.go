@@ -0,0 +1,68 @@
+package providers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	data := TemplateData{
+		Name:    "myApp",
+		Options: map[string]string{"module": "example.com/app", "typescript": "true"},
+		Config:  map[string]string{"projectDir": "/projects"},
+	}
+
+	cases := map[string]string{
+		"{{.Name}}":                            "myApp",
+		"{{kebab .Name}}":                      "my-app",
+		"{{snake .Name}}":                      "my_app",
+		"{{pascal \"my-api server\"}}":         "MyApiServer",
+		"{{camel \"my-api server\"}}":          "myApiServer",
+		"{{upper .Name}}":                      "MYAPP",
+		"pkg@{{default \"latest\" .Version}}":  "pkg@latest",
+		"{{if has \"typescript\"}}--ts{{end}}": "--ts",
+		"{{ternary \"a\" \"b\" (eq (opt \"typescript\") \"false\")}}": "b",
+		"{{.Config.projectDir}}/{{.Name}}":                            "/projects/myApp",
+		"{{.Options.missing}}":                                        "",
+		"{{join \"|\" (split \"/\" \"a,b/ c /\")}}":                   "a,b|c",
+	}
+
+	for input, expected := range cases {
+		got, err := RenderTemplate(input, data)
+		if err != nil {
+			t.Errorf("Unexpected error for %q: %v", input, err)
+			continue
+		}
+		if got != expected {
+			t.Errorf("Expected %q to render %q, got %q", input, expected, got)
+		}
+	}
+
+	t.Run("Legacy placeholders are translated", func(t *testing.T) {
+		args, err := renderArgs([]string{"create-app@{version}", "{project-name}", "{module}"}, TemplateData{Name: "app", Options: map[string]string{}})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		expected := []string{"create-app@latest", "app", "github.com/example/app"}
+		if !reflect.DeepEqual(args, expected) {
+			t.Errorf("Expected %v, got %v", expected, args)
+		}
+	})
+
+	t.Run("Empty args are dropped", func(t *testing.T) {
+		args, err := renderArgs([]string{"new", "{{if has \"git\"}}--git{{end}}"}, data)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(args, []string{"new"}) {
+			t.Errorf("Expected [new], got %v", args)
+		}
+	})
+
+	t.Run("Invalid template is reported", func(t *testing.T) {
+		if _, err := RenderTemplate("{{.Name", data); err == nil {
+			t.Errorf("Expected error, got nil")
+		}
+	})
+}
@@ -0,0 +1,63 @@
+package providers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Project identifies the project being created
+type Project struct {
+	// Name is the project name, also used as its directory name
+	Name string
+	// Dir is the directory the project is created in; empty means the
+	// current directory
+	Dir string
+	// Args are raw generator arguments given after "--", passed through
+	// unchanged
+	Args []string
+}
+
+// Path returns the project's own directory
+func (p Project) Path() string {
+	return filepath.Join(p.Dir, p.Name)
+}
+
+// ProjectBootstrapper is implemented by providers that can create a project
+// in a directory other than the current one
+type ProjectBootstrapper interface {
+	BootstrapProject(project Project, options map[string]string) error
+}
+
+// CommandTemplater is implemented by providers that run a generator command
+// built from templates (see TemplateData)
+type CommandTemplater interface {
+	CommandTemplate() (command string, args []string)
+}
+
+// BootstrapIn creates the project with p. Providers that do not implement
+// ProjectBootstrapper are run from inside project.Dir instead.
+func BootstrapIn(p Provider, project Project, options map[string]string) error {
+	if pb, ok := p.(ProjectBootstrapper); ok {
+		return pb.BootstrapProject(project, options)
+	}
+
+	if len(project.Args) > 0 {
+		return fmt.Errorf("%s does not accept pass-through arguments", p.Name())
+	}
+
+	if project.Dir == "" {
+		return p.Bootstrap(project.Name, options)
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	if err := os.Chdir(project.Dir); err != nil {
+		return fmt.Errorf("failed to enter %s: %v", project.Dir, err)
+	}
+	defer os.Chdir(wd)
+
+	return p.Bootstrap(project.Name, options)
+}
@@ -1,8 +1,8 @@
 package providers
 
 import (
-	"errors"
 	"sort"
+	"strings"
 )
 
 // Provider defines the interface for framework providers
@@ -31,18 +31,38 @@ type Provider interface {
 // Registry keeps track of all registered providers
 var Registry = make(map[string]Provider)
 
-// Register adds a provider to the registry
+// lowerNames maps lower-cased provider names to their names, so Get can
+// match names in any case
+var lowerNames = make(map[string]string)
+
+// Register adds a provider, and any aliases it declares, to the registry
 func Register(p Provider) {
 	Registry[p.Name()] = p
+	lowerNames[strings.ToLower(p.Name())] = p.Name()
+	if a, ok := p.(Aliaser); ok {
+		for _, alias := range a.Aliases() {
+			RegisterAlias(alias, p.Name())
+		}
+	}
 }
 
-// Get returns a provider by name
+// Get returns a provider by name or alias, ignoring case. Unknown names
+// return a *NotFoundError with suggestions.
 func Get(name string) (Provider, error) {
-	provider, exists := Registry[name]
-	if !exists {
-		return nil, errors.New("provider not found: " + name)
+	if provider, exists := Registry[name]; exists {
+		return provider, nil
+	}
+	if providerName, ok := lowerNames[strings.ToLower(name)]; ok {
+		if provider, exists := Registry[providerName]; exists {
+			return provider, nil
+		}
+	}
+	if target, ok := aliases[strings.ToLower(name)]; ok {
+		if provider, exists := Registry[target]; exists {
+			return provider, nil
+		}
 	}
-	return provider, nil
+	return nil, &NotFoundError{Name: name, Suggestions: Suggest(name)}
 }
 
 // List returns all registered providers
@@ -1,6 +1,7 @@
 package providers
 
 import (
+	"encoding/json"
 	"testing"
 )
 
@@ -62,6 +63,77 @@ func TestProviderRegistry(t *testing.T) {
 	})
 }
 
+// resetRegistry empties the registry and aliases for a test and restores
+// them when it finishes
+func resetRegistry(t *testing.T) {
+	t.Helper()
+	registry, names, aliasNames := Registry, lowerNames, aliases
+	t.Cleanup(func() {
+		Registry, lowerNames, aliases = registry, names, aliasNames
+	})
+	Registry = make(map[string]Provider)
+	lowerNames = make(map[string]string)
+	aliases = make(map[string]string)
+}
+
+func TestAliases(t *testing.T) {
+	resetRegistry(t)
+
+	for _, name := range []string{"next", "laravel", "go", "vue"} {
+		Register(&ProviderDefinition{ProviderName: name, AliasNames: []string{name + "js"}})
+	}
+	RegisterAlias("golang", "go")
+
+	t.Run("Get resolves aliases and names in any case", func(t *testing.T) {
+		for _, name := range []string{"nextjs", "NextJS", "golang", "Golang", "NEXT"} {
+			if _, err := Get(name); err != nil {
+				t.Errorf("Expected %s to resolve, got %v", name, err)
+			}
+		}
+	})
+
+	t.Run("AliasesFor lists aliases sorted", func(t *testing.T) {
+		got := AliasesFor("go")
+		if len(got) != 2 || got[0] != "gojs" || got[1] != "golang" {
+			t.Errorf("Expected [gojs golang], got %v", got)
+		}
+	})
+
+	t.Run("Unknown names suggest close matches", func(t *testing.T) {
+		tests := map[string]string{
+			"larvel": "laravel",
+			"nxt":    "next",
+			"vu":     "vue",
+			"lara":   "laravel",
+		}
+		for name, want := range tests {
+			_, err := Get(name)
+			notFound, ok := err.(*NotFoundError)
+			if !ok {
+				t.Fatalf("Expected *NotFoundError for %s, got %v", name, err)
+			}
+			if len(notFound.Suggestions) == 0 || notFound.Suggestions[0] != want {
+				t.Errorf("Expected %s to suggest %s, got %v", name, want, notFound.Suggestions)
+			}
+		}
+	})
+
+	t.Run("Unrelated names suggest nothing", func(t *testing.T) {
+		if got := Suggest("kubernetes"); len(got) != 0 {
+			t.Errorf("Expected no suggestions, got %v", got)
+		}
+	})
+
+	t.Run("Levenshtein distance", func(t *testing.T) {
+		if d := levenshtein("kitten", "sitting"); d != 3 {
+			t.Errorf("Expected distance 3, got %d", d)
+		}
+		if d := levenshtein("", "go"); d != 2 {
+			t.Errorf("Expected distance 2, got %d", d)
+		}
+	})
+}
+
 func TestRemoteProviders(t *testing.T) {
 	t.Run("Remote provider adapter", func(t *testing.T) {
 		adapter := &ProviderAdapter{
@@ -131,3 +203,75 @@ func (p *MockProvider) CheckDependencies() error {
 func (p *MockProvider) SupportedVersions() []string {
 	return p.Versions
 }
+
+func TestDependencies(t *testing.T) {
+	t.Run("Reports found and missing dependencies", func(t *testing.T) {
+		def := &ProviderDefinition{
+			ProviderName: "demo",
+			DependsOn:    []Dependency{{Command: "go"}, {Command: "bt-command-that-does-not-exist"}},
+		}
+
+		statuses := Dependencies(def)
+		if len(statuses) != 2 {
+			t.Fatalf("Expected 2 statuses, got %d", len(statuses))
+		}
+
+		if !statuses[0].Found || statuses[0].Path == "" || statuses[0].Version == "" {
+			t.Errorf("Expected go to be found with a path and version, got %+v", statuses[0])
+		}
+
+		if statuses[1].Found {
+			t.Errorf("Expected missing dependency to be reported, got %+v", statuses[1])
+		}
+	})
+
+	t.Run("Providers without reporter have no statuses", func(t *testing.T) {
+		if statuses := Dependencies(&MockProvider{NameValue: "mock"}); statuses != nil {
+			t.Errorf("Expected nil, got %v", statuses)
+		}
+	})
+}
+
+func TestCheckDependencies(t *testing.T) {
+	t.Run("Reports unmet version constraints", func(t *testing.T) {
+		def := &ProviderDefinition{
+			ProviderName: "demo",
+			DependsOn: []Dependency{
+				{Command: "go", Version: ">=1.0", VersionArgs: []string{"version"}},
+				{Command: "go", Version: ">=999", VersionArgs: []string{"version"}},
+				{Command: "bt-command-that-does-not-exist", Version: ">=1"},
+			},
+		}
+
+		err := def.CheckDependencies()
+		depErr, ok := err.(*DependencyError)
+		if !ok {
+			t.Fatalf("Expected *DependencyError, got %v", err)
+		}
+
+		if len(depErr.Unmet) != 2 {
+			t.Fatalf("Expected 2 unmet dependencies, got %+v", depErr.Unmet)
+		}
+		if depErr.Unmet[0].Found == "" || depErr.Unmet[0].Required != ">=999" {
+			t.Errorf("Expected found and required versions, got %+v", depErr.Unmet[0])
+		}
+		if !depErr.Unmet[1].Missing {
+			t.Errorf("Expected missing dependency, got %+v", depErr.Unmet[1])
+		}
+	})
+
+	t.Run("Dependencies decode from names and objects", func(t *testing.T) {
+		var def ProviderDefinition
+		err := json.Unmarshal([]byte(`{"dependencies": ["npx", {"command": "node", "version": ">=18"}]}`), &def)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(def.DependsOn) != 2 || def.DependsOn[1].Version != ">=18" {
+			t.Errorf("Dependencies not decoded as expected: %+v", def.DependsOn)
+		}
+
+		if err := json.Unmarshal([]byte(`{"dependencies": [{"command": "node", "version": ">>18"}]}`), &def); err == nil {
+			t.Errorf("Expected error for invalid constraint, got nil")
+		}
+	})
+}
@@ -1,24 +1,26 @@
 package providers
 
 import (
-	"encoding/json"
 	"fmt"
-	"os"
-	"os/exec"
-	"path/filepath"
-	"strings"
-
-	"github.com/sharik709/bootstraper/util"
+	"sort"
 )
 
 type ProviderDefinition struct {
 	ProviderName string            `json:"name"`
 	ProviderDesc string            `json:"description"`
+	AliasNames   []string          `json:"aliases,omitempty"`
 	Command      string            `json:"command"`
 	CommandArgs  []string          `json:"args"`
-	DependsOn    []string          `json:"dependencies"`
-	Options      map[string]string `json:"options"`
+	DependsOn    []Dependency      `json:"dependencies"`
+	Options      map[string]Option `json:"options"`
 	Versions     []string          `json:"versions,omitempty"`
+	Env          map[string]string `json:"env,omitempty"`
+	Dir          string            `json:"dir,omitempty"`
+	Pre          []Step            `json:"pre,omitempty"`
+	Post         []Step            `json:"post,omitempty"`
+	Passthrough  *PassthroughSpec  `json:"passthrough,omitempty"`
+	// VersionSource is where versions are discovered; Versions is used without it
+	VersionSource *VersionSource `json:"version_source,omitempty"`
 }
 
 type ProviderRegistry struct {
@@ -34,140 +36,137 @@ func (p *ProviderDefinition) Description() string {
 	return p.ProviderDesc
 }
 
+// Aliases returns the other names the provider can be referred to by
+func (p *ProviderDefinition) Aliases() []string {
+	return p.AliasNames
+}
+
 func (p *ProviderDefinition) Bootstrap(projectName string, options map[string]string) error {
-	for _, dep := range p.DependsOn {
-		if !util.CommandExists(dep) {
-			return fmt.Errorf("dependency not found: %s", dep)
-		}
+	return p.BootstrapProject(Project{Name: projectName}, options)
+}
+
+// BootstrapProject creates the project inside project.Dir
+func (p *ProviderDefinition) BootstrapProject(project Project, options map[string]string) error {
+	options, err := ValidateOptions(p.OptionSchema(), options)
+	if err != nil {
+		return err
 	}
 
-	args := make([]string, len(p.CommandArgs))
-	copy(args, p.CommandArgs)
+	if err := p.CheckDependencies(); err != nil {
+		return err
+	}
 
-	// Replace project name placeholder
-	for i, arg := range args {
-		if arg == "{project-name}" {
-			args[i] = projectName
-		}
+	plan, err := p.Plan(project, options)
+	if err != nil {
+		return err
 	}
 
-	// Handle version placeholders
-	version, hasVersion := options["version"]
-	for i, arg := range args {
-		if strings.Contains(arg, "{version}") {
-			if hasVersion && version != "" {
-				args[i] = strings.ReplaceAll(arg, "{version}", version)
-			} else {
-				// Use "latest" if no version specified or remove version tag altogether
-				if strings.Contains(arg, "@{version}") {
-					// For patterns like package@{version}, use latest
-					args[i] = strings.ReplaceAll(arg, "@{version}", "@latest")
-				} else {
-					// For other cases, just remove the {version} placeholder
-					args[i] = strings.ReplaceAll(arg, "{version}", "")
-				}
-			}
-		}
+	fmt.Printf("Creating %s project: %s\n", p.ProviderName, project.Name)
+	return RunPlan(plan)
+}
+
+// Plan renders the provider's pre steps, main command and post steps into
+// the invocations Bootstrap would run. Steps whose condition does not hold
+// are left out. project.Args are placed in the main command as declared by
+// the provider's pass-through spec. Relative step directories are resolved against project.Dir.
+// Options are expected to be validated.
+func (p *ProviderDefinition) Plan(project Project, options map[string]string) ([]Invocation, error) {
+	data := NewTemplateData(project, options)
+
+	main := Step{
+		Name:    "main",
+		Command: p.Command,
+		Args:    p.CommandArgs,
+		Dir:     p.Dir,
+		Env:     p.Env,
 	}
 
-	// Handle module placeholder (specific to Go)
-	if module, ok := options["module"]; ok && module != "" {
-		for i, arg := range args {
-			if arg == "{module}" {
-				args[i] = module
-			}
+	var plan []Invocation
+	for _, step := range p.Pre {
+		inv, ok, err := renderStep(step, data)
+		if err != nil {
+			return nil, err
 		}
-	} else {
-		// If module is a placeholder but not provided, use a default based on project name
-		for i, arg := range args {
-			if arg == "{module}" {
-				args[i] = "github.com/example/" + projectName
-			}
+		if ok {
+			plan = append(plan, inv)
 		}
 	}
 
-	// Add command-line flags from options
-	for flagName, flagValue := range options {
-		if flagName == "version" || flagName == "module" {
-			continue // Already handled
-		}
+	inv, _, err := renderStep(main, data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Render options as declared by the schema, then add pass-through args
+	optionArgs, optionEnv := RenderOptions(p.OptionSchema(), options)
+	if inv.Args, err = placeArgs(p.ProviderName, p.Passthrough, inv.Args, optionArgs, project.Args); err != nil {
+		return nil, err
+	}
+	inv.Env = append(inv.Env, optionEnv...)
+	plan = append(plan, inv)
 
-		if flagValue == "true" {
-			args = append(args, fmt.Sprintf("--%s", flagName))
-		} else if flagValue != "false" && flagValue != "" {
-			args = append(args, fmt.Sprintf("--%s=%s", flagName, flagValue))
+	for _, step := range p.Post {
+		inv, ok, err := renderStep(step, data)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			plan = append(plan, inv)
 		}
 	}
 
-	cmd := exec.Command(p.Command, args...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	cmd.Stdin = os.Stdin
+	for i := range plan {
+		plan[i].Dir = resolvePath(project.Dir, plan[i].Dir)
+	}
+
+	return plan, nil
+}
 
-	fmt.Printf("Creating %s project: %s\n", p.ProviderName, projectName)
-	return cmd.Run()
+// CommandTemplate returns the generator command and its unrendered args
+func (p *ProviderDefinition) CommandTemplate() (string, []string) {
+	return p.Command, p.CommandArgs
 }
 
 func (p *ProviderDefinition) AvailableOptions() map[string]string {
-	return p.Options
+	options := make(map[string]string, len(p.Options))
+	for name, opt := range p.Options {
+		options[name] = opt.Help
+	}
+	return options
 }
 
-func (p *ProviderDefinition) CheckDependencies() error {
-	for _, dep := range p.DependsOn {
-		if !util.CommandExists(dep) {
-			return fmt.Errorf("dependency not found: %s", dep)
-		}
+// OptionSchema returns the typed option schema sorted by name
+func (p *ProviderDefinition) OptionSchema() []Option {
+	options := make([]Option, 0, len(p.Options))
+	for name, opt := range p.Options {
+		opt.Name = name
+		options = append(options, opt)
 	}
-	return nil
+	sortOptions(options)
+	return options
+}
+
+// CheckDependencies returns a *DependencyError listing every dependency that
+// is missing or does not satisfy its version constraint
+func (p *ProviderDefinition) CheckDependencies() error {
+	return checkDependencies(p.ProviderName, p.DependsOn)
 }
 
 func (p *ProviderDefinition) SupportedVersions() []string {
 	return p.Versions
 }
 
-func loadProviders() ([]Provider, error) {
-	registryPath := "providers/registry.json"
-
-	data, err := os.ReadFile(registryPath)
-	if err != nil {
-		// Try with absolute path from executable
-		executablePath, err := os.Executable()
-		if err != nil {
-			return nil, fmt.Errorf("failed to get executable path: %v", err)
-		}
-
-		executableDir := filepath.Dir(executablePath)
-		registryPath = filepath.Join(executableDir, "providers", "registry.json")
-
-		data, err = os.ReadFile(registryPath)
-		if err != nil {
-			return nil, fmt.Errorf("failed to read provider registry: %v", err)
-		}
-	}
-
-	var registry ProviderRegistry
-	if err := json.Unmarshal(data, &registry); err != nil {
-		return nil, fmt.Errorf("failed to parse provider registry: %v", err)
-	}
-
-	providers := make([]Provider, 0, len(registry.Providers))
-	for _, providerDef := range registry.Providers {
-		provider := providerDef // Create local copy to avoid referencing loop variable
-		providers = append(providers, &provider)
-	}
-
-	return providers, nil
+// ReleaseSource returns the package registry the provider's versions come from
+func (p *ProviderDefinition) ReleaseSource() *VersionSource {
+	return p.VersionSource
 }
 
-func init() {
-	// Load providers from registry.json
-	jsonProviders, err := loadProviders()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Warning: Failed to load providers from registry.json: %v\n", err)
-		return
-	}
-
-	for _, provider := range jsonProviders {
-		Register(provider)
+// sortedKeys returns the keys of m in sorted order
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
 	}
+	sort.Strings(keys)
+	return keys
 }
@@ -0,0 +1,120 @@
+package providers
+
+import (
+	"sort"
+)
+
+// RenderSpec declares how an option is turned into command-line arguments or
+// environment variables. The zero value renders "--<name>" for true bools and
+// "--<name>=<value>" for everything else.
+type RenderSpec struct {
+	// Flag replaces the default "--<name>", e.g. "--ts" or "-t"
+	Flag string `json:"flag,omitempty"`
+	// Negate is rendered when a bool option is false, e.g. "--no-eslint"
+	Negate string `json:"negate,omitempty"`
+	// Separator between flag and value: "=" (default) or " " for a separate argument
+	Separator string `json:"separator,omitempty"`
+	// Position renders the bare value as a positional argument; positional
+	// arguments come before flags, in ascending position order
+	Position int `json:"position,omitempty"`
+	// Env sets the named environment variable to the value instead of adding an argument
+	Env string `json:"env,omitempty"`
+	// Repeat renders a list option as one flag per item instead of a comma-separated value
+	Repeat bool `json:"repeat,omitempty"`
+	// Skip leaves the option out of the rendered arguments, for options only
+	// consumed by placeholders in the provider args
+	Skip bool `json:"skip,omitempty"`
+}
+
+// placeholderOptions are consumed by the legacy {version} and {module} arg
+// placeholders and are not rendered unless they declare a render spec
+var placeholderOptions = map[string]bool{
+	"version": true,
+	"module":  true,
+}
+
+// RenderOptions turns option values into command-line arguments and
+// environment variables ("NAME=value"). Output is deterministic: positional
+// arguments first, ordered by position, then flags in schema (name) order.
+func RenderOptions(schema []Option, values map[string]string) (args []string, env []string) {
+	var positional []Option
+	for _, opt := range schema {
+		value, ok := values[opt.Name]
+		if !ok || value == "" {
+			continue
+		}
+
+		spec := RenderSpec{}
+		if opt.Render != nil {
+			spec = *opt.Render
+		} else if placeholderOptions[opt.Name] {
+			continue
+		}
+
+		switch {
+		case spec.Skip:
+		case spec.Env != "":
+			env = append(env, spec.Env+"="+value)
+		case spec.Position > 0:
+			positional = append(positional, opt)
+		default:
+			args = append(args, renderFlag(opt, spec, value)...)
+		}
+	}
+
+	sort.SliceStable(positional, func(i, j int) bool {
+		return positional[i].Render.Position < positional[j].Render.Position
+	})
+
+	var positionalArgs []string
+	for _, opt := range positional {
+		value := values[opt.Name]
+		if opt.Type == OptionList && opt.Render.Repeat {
+			positionalArgs = append(positionalArgs, splitList(value)...)
+			continue
+		}
+		positionalArgs = append(positionalArgs, value)
+	}
+
+	return append(positionalArgs, args...), env
+}
+
+// renderFlag renders a single non-positional option
+func renderFlag(opt Option, spec RenderSpec, value string) []string {
+	flag := spec.Flag
+	if flag == "" {
+		flag = "--" + opt.Name
+	}
+
+	if opt.Type == OptionBool {
+		switch value {
+		case "true":
+			return []string{flag}
+		case "false":
+			if spec.Negate != "" {
+				return []string{spec.Negate}
+			}
+			return nil
+		}
+	}
+
+	values := []string{value}
+	if opt.Type == OptionList && spec.Repeat {
+		values = splitList(value)
+	}
+
+	var args []string
+	for _, v := range values {
+		if spec.Separator == " " {
+			args = append(args, flag, v)
+			continue
+		}
+
+		separator := spec.Separator
+		if separator == "" {
+			separator = "="
+		}
+		args = append(args, flag+separator+v)
+	}
+	return args
+}
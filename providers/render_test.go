@@ -0,0 +1,60 @@
+package providers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRenderOptions(t *testing.T) {
+	schema := []Option{
+		{Name: "eslint", Type: OptionBool, Render: &RenderSpec{Negate: "--no-eslint"}},
+		{Name: "org", Type: OptionString, Render: &RenderSpec{Flag: "-o", Separator: " "}},
+		{Name: "platforms", Type: OptionList, Render: &RenderSpec{Repeat: true}},
+		{Name: "target", Type: OptionString, Render: &RenderSpec{Position: 2}},
+		{Name: "template", Type: OptionString, Render: &RenderSpec{Position: 1}},
+		{Name: "token", Type: OptionString, Render: &RenderSpec{Env: "GEN_TOKEN"}},
+		{Name: "typescript", Type: OptionBool, Render: &RenderSpec{Flag: "--ts"}},
+		{Name: "version", Type: OptionString},
+		{Name: "view", Type: OptionEnum, Values: []string{"pug", "ejs"}},
+	}
+
+	t.Run("Renders every form deterministically", func(t *testing.T) {
+		values := map[string]string{
+			"eslint":     "false",
+			"org":        "com.example",
+			"platforms":  "ios,web",
+			"target":     "dist",
+			"template":   "minimal",
+			"token":      "secret",
+			"typescript": "true",
+			"version":    "1.0.0",
+			"view":       "pug",
+		}
+
+		expectedArgs := []string{
+			"minimal", "dist",
+			"--no-eslint",
+			"-o", "com.example",
+			"--platforms=ios", "--platforms=web",
+			"--ts",
+			"--view=pug",
+		}
+
+		for i := 0; i < 10; i++ {
+			args, env := RenderOptions(schema, values)
+			if !reflect.DeepEqual(args, expectedArgs) {
+				t.Fatalf("Expected args %v, got %v", expectedArgs, args)
+			}
+			if !reflect.DeepEqual(env, []string{"GEN_TOKEN=secret"}) {
+				t.Fatalf("Expected env [GEN_TOKEN=secret], got %v", env)
+			}
+		}
+	})
+
+	t.Run("False bool without negation is omitted", func(t *testing.T) {
+		args, _ := RenderOptions(schema, map[string]string{"typescript": "false"})
+		if len(args) != 0 {
+			t.Errorf("Expected no args, got %v", args)
+		}
+	})
+}
@@ -0,0 +1,153 @@
+package providers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"unicode"
+)
+
+// npmResolver lists package versions from the npm registry
+type npmResolver struct{}
+
+func (npmResolver) DefaultURL() string { return "https://registry.npmjs.org" }
+
+func (npmResolver) Releases(client *http.Client, baseURL, pkg string) (*Releases, error) {
+	var doc struct {
+		DistTags map[string]string          `json:"dist-tags"`
+		Versions map[string]json.RawMessage `json:"versions"`
+	}
+	// Scoped packages keep their "@" but escape the "/"
+	name := strings.Replace(url.PathEscape(pkg), "%40", "@", 1)
+	if err := getJSON(client, baseURL+"/"+name, "application/vnd.npm.install-v1+json", &doc); err != nil {
+		return nil, err
+	}
+
+	releases := &Releases{Tags: doc.DistTags}
+	for version := range doc.Versions {
+		releases.Versions = append(releases.Versions, version)
+	}
+	return releases, nil
+}
+
+// packagistResolver lists tagged releases from Packagist
+type packagistResolver struct{}
+
+func (packagistResolver) DefaultURL() string { return "https://repo.packagist.org" }
+
+func (packagistResolver) Releases(client *http.Client, baseURL, pkg string) (*Releases, error) {
+	var doc struct {
+		Packages map[string][]struct {
+			Version string `json:"version"`
+		} `json:"packages"`
+	}
+	if err := getJSON(client, baseURL+"/p2/"+pkg+".json", "application/json", &doc); err != nil {
+		return nil, err
+	}
+
+	releases := &Releases{}
+	for _, release := range doc.Packages[pkg] {
+		releases.Versions = append(releases.Versions, release.Version)
+	}
+	return releases, nil
+}
+
+// pypiResolver lists releases from the PyPI JSON API
+type pypiResolver struct{}
+
+func (pypiResolver) DefaultURL() string { return "https://pypi.org" }
+
+func (pypiResolver) Releases(client *http.Client, baseURL, pkg string) (*Releases, error) {
+	var doc struct {
+		Info struct {
+			Version string `json:"version"`
+		} `json:"info"`
+		Releases map[string][]json.RawMessage `json:"releases"`
+	}
+	if err := getJSON(client, baseURL+"/pypi/"+url.PathEscape(pkg)+"/json", "application/json", &doc); err != nil {
+		return nil, err
+	}
+
+	releases := &Releases{}
+	if doc.Info.Version != "" {
+		releases.Tags = map[string]string{"latest": doc.Info.Version}
+	}
+	for version, files := range doc.Releases {
+		// Releases without files were yanked or never uploaded
+		if len(files) > 0 {
+			releases.Versions = append(releases.Versions, version)
+		}
+	}
+	return releases, nil
+}
+
+// goproxyResolver lists module versions from a Go module proxy
+type goproxyResolver struct{}
+
+func (goproxyResolver) DefaultURL() string { return "https://proxy.golang.org" }
+
+func (goproxyResolver) Releases(client *http.Client, baseURL, pkg string) (*Releases, error) {
+	body, err := get(client, baseURL+"/"+escapeModulePath(pkg)+"/@v/list", "")
+	if err != nil {
+		return nil, err
+	}
+
+	releases := &Releases{}
+	for _, line := range strings.Split(string(body), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			releases.Versions = append(releases.Versions, line)
+		}
+	}
+	return releases, nil
+}
+
+// escapeModulePath applies the module proxy case encoding, which writes
+// upper-case letters as "!" followed by the lower-case letter
+func escapeModulePath(path string) string {
+	var b strings.Builder
+	for _, r := range path {
+		if unicode.IsUpper(r) {
+			b.WriteByte('!')
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// pubdevResolver lists package versions from pub.dev
+type pubdevResolver struct{}
+
+func (pubdevResolver) DefaultURL() string { return "https://pub.dev" }
+
+func (pubdevResolver) Releases(client *http.Client, baseURL, pkg string) (*Releases, error) {
+	var doc struct {
+		Latest struct {
+			Version string `json:"version"`
+		} `json:"latest"`
+		Versions []struct {
+			Version string `json:"version"`
+		} `json:"versions"`
+	}
+	if err := getJSON(client, baseURL+"/api/packages/"+url.PathEscape(pkg), "application/vnd.pub.v2+json", &doc); err != nil {
+		return nil, err
+	}
+
+	releases := &Releases{}
+	if doc.Latest.Version != "" {
+		releases.Tags = map[string]string{"latest": doc.Latest.Version}
+	}
+	for _, release := range doc.Versions {
+		releases.Versions = append(releases.Versions, release.Version)
+	}
+	return releases, nil
+}
+
+func init() {
+	RegisterVersionResolver("npm", npmResolver{})
+	RegisterVersionResolver("packagist", packagistResolver{})
+	RegisterVersionResolver("pypi", pypiResolver{})
+	RegisterVersionResolver("goproxy", goproxyResolver{})
+	RegisterVersionResolver("pubdev", pubdevResolver{})
+}
@@ -0,0 +1,253 @@
+package providers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// cachedRegistryFile is the remote registry copy stored in the cache directory
+	cachedRegistryFile = "registry.json"
+
+	// cachedRegistryMetaFile holds revalidation data for the cached registry
+	cachedRegistryMetaFile = "registry.meta.json"
+
+	// maxRegistrySize guards against unexpectedly large responses
+	maxRegistrySize = 10 << 20
+)
+
+// SyncStatus describes the outcome of a registry sync
+type SyncStatus string
+
+const (
+	// SyncUpdated means a new registry was downloaded and cached
+	SyncUpdated SyncStatus = "updated"
+
+	// SyncNotModified means the server confirmed the cached registry is current
+	SyncNotModified SyncStatus = "not-modified"
+
+	// SyncOffline means the remote registry could not be reached and a
+	// fallback copy (cached or embedded) is in use
+	SyncOffline SyncStatus = "offline"
+)
+
+// RegistryCacheMeta records where a cached registry came from and how to revalidate it
+type RegistryCacheMeta struct {
+	URL          string    `json:"url"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	SHA256       string    `json:"sha256"`
+	UpdatedAt    string    `json:"updated_at"`
+	FetchedAt    time.Time `json:"fetched_at"`
+}
+
+// SyncResult is returned by SyncRegistry
+type SyncResult struct {
+	Status SyncStatus
+	// Source is "remote", "cache" or "embedded" and names the registry now in effect
+	Source   string
+	Registry *ProviderRegistry
+	// Err is the network error that caused an offline fallback
+	Err error
+}
+
+// SyncOptions configures SyncRegistry
+type SyncOptions struct {
+	// URL of the remote registry
+	URL string
+	// CacheDir is where the registry and its metadata are stored
+	CacheDir string
+	// SHA256 optionally pins the expected checksum of the downloaded registry
+	SHA256 string
+	// Client is the HTTP client to use; a client with a timeout is used if nil
+	Client *http.Client
+}
+
+// SyncRegistry fetches the remote provider registry into the cache directory.
+// The cached copy is revalidated with ETag/Last-Modified so an unchanged
+// registry is not downloaded again. When the server cannot be reached the
+// cached registry (or the embedded one) is reported as the fallback instead
+// of failing. A checksum mismatch or an invalid document is always an error
+// and leaves the cache untouched.
+func SyncRegistry(opts SyncOptions) (*SyncResult, error) {
+	if opts.URL == "" {
+		return nil, errors.New("registry URL is not configured")
+	}
+	if opts.CacheDir == "" {
+		return nil, errors.New("cache directory is not configured")
+	}
+
+	client := opts.Client
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	meta, _ := loadRegistryMeta(opts.CacheDir)
+	cached, _ := loadCachedRegistry(opts.CacheDir)
+
+	req, err := http.NewRequest(http.MethodGet, opts.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid registry URL: %v", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if cached != nil && meta != nil && meta.URL == opts.URL {
+		if meta.ETag != "" {
+			req.Header.Set("If-None-Match", meta.ETag)
+		}
+		if meta.LastModified != "" {
+			req.Header.Set("If-Modified-Since", meta.LastModified)
+		}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return offlineResult(cached, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached != nil:
+		meta.FetchedAt = time.Now().UTC()
+		if err := saveRegistryMeta(opts.CacheDir, meta); err != nil {
+			return nil, err
+		}
+		return &SyncResult{Status: SyncNotModified, Source: "cache", Registry: cached}, nil
+	case resp.StatusCode != http.StatusOK:
+		return offlineResult(cached, fmt.Errorf("unexpected response from %s: %s", opts.URL, resp.Status))
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxRegistrySize+1))
+	if err != nil {
+		return offlineResult(cached, err)
+	}
+	if len(data) > maxRegistrySize {
+		return nil, fmt.Errorf("registry at %s exceeds %d bytes", opts.URL, maxRegistrySize)
+	}
+
+	checksum := sha256Hex(data)
+	if opts.SHA256 != "" && !strings.EqualFold(opts.SHA256, checksum) {
+		return nil, fmt.Errorf("registry checksum mismatch: expected %s, got %s", opts.SHA256, checksum)
+	}
+
+	registry, err := parseRegistry(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse remote registry: %v", err)
+	}
+	if len(registry.Providers) == 0 {
+		return nil, fmt.Errorf("remote registry at %s contains no providers", opts.URL)
+	}
+
+	if err := os.MkdirAll(opts.CacheDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %v", err)
+	}
+	if err := writeFileAtomic(filepath.Join(opts.CacheDir, cachedRegistryFile), data); err != nil {
+		return nil, fmt.Errorf("failed to cache registry: %v", err)
+	}
+
+	newMeta := &RegistryCacheMeta{
+		URL:          opts.URL,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		SHA256:       checksum,
+		UpdatedAt:    registry.UpdatedAt,
+		FetchedAt:    time.Now().UTC(),
+	}
+	if err := saveRegistryMeta(opts.CacheDir, newMeta); err != nil {
+		return nil, err
+	}
+
+	return &SyncResult{Status: SyncUpdated, Source: "remote", Registry: registry}, nil
+}
+
+// offlineResult reports the registry that stays in effect when the remote one is unavailable
+func offlineResult(cached *ProviderRegistry, cause error) (*SyncResult, error) {
+	if cached != nil {
+		return &SyncResult{Status: SyncOffline, Source: "cache", Registry: cached, Err: cause}, nil
+	}
+
+	embedded, err := parseRegistry(embeddedRegistry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse built-in provider registry: %v", err)
+	}
+	return &SyncResult{Status: SyncOffline, Source: "embedded", Registry: embedded, Err: cause}, nil
+}
+
+// loadCachedRegistry returns the cached remote registry if it is present and
+// its checksum matches the one recorded when it was downloaded
+func loadCachedRegistry(cacheDir string) (*ProviderRegistry, error) {
+	meta, err := loadRegistryMeta(cacheDir)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(cacheDir, cachedRegistryFile))
+	if err != nil {
+		return nil, err
+	}
+
+	if sha256Hex(data) != meta.SHA256 {
+		return nil, fmt.Errorf("cached registry checksum mismatch, run 'bt registry update'")
+	}
+
+	return parseRegistry(data)
+}
+
+func loadRegistryMeta(cacheDir string) (*RegistryCacheMeta, error) {
+	data, err := os.ReadFile(filepath.Join(cacheDir, cachedRegistryMetaFile))
+	if err != nil {
+		return nil, err
+	}
+
+	var meta RegistryCacheMeta
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, fmt.Errorf("failed to parse registry cache metadata: %v", err)
+	}
+	return &meta, nil
+}
+
+func saveRegistryMeta(cacheDir string, meta *RegistryCacheMeta) error {
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal registry cache metadata: %v", err)
+	}
+	if err := writeFileAtomic(filepath.Join(cacheDir, cachedRegistryMetaFile), data); err != nil {
+		return fmt.Errorf("failed to write registry cache metadata: %v", err)
+	}
+	return nil
+}
+
+// writeFileAtomic writes data to a temporary file and renames it into place
+// so readers never observe a partially written file
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,112 @@
+package providers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testRemoteRegistry = `{
+  "providers": [
+    {"name": "remote-only", "description": "Remote provider", "command": "remote-gen"}
+  ],
+  "updated_at": "2999-01-01"
+}`
+
+func TestSyncRegistry(t *testing.T) {
+	t.Run("Downloads, caches and revalidates", func(t *testing.T) {
+		requests := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", `"v1"`)
+			w.Write([]byte(testRemoteRegistry))
+		}))
+		defer server.Close()
+
+		cacheDir := t.TempDir()
+		opts := SyncOptions{URL: server.URL, CacheDir: cacheDir}
+
+		result, err := SyncRegistry(opts)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result.Status != SyncUpdated {
+			t.Errorf("Expected status %s, got %s", SyncUpdated, result.Status)
+		}
+
+		result, err = SyncRegistry(opts)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result.Status != SyncNotModified {
+			t.Errorf("Expected status %s, got %s", SyncNotModified, result.Status)
+		}
+		if requests != 2 {
+			t.Errorf("Expected 2 requests, got %d", requests)
+		}
+
+		defs, err := loadDefinitions(cacheDir)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if findDefinition(defs, "remote-only") == nil {
+			t.Errorf("Expected cached registry to be used")
+		}
+	})
+
+	t.Run("Rejects checksum mismatch", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(testRemoteRegistry))
+		}))
+		defer server.Close()
+
+		cacheDir := t.TempDir()
+		_, err := SyncRegistry(SyncOptions{URL: server.URL, CacheDir: cacheDir, SHA256: "deadbeef"})
+		if err == nil {
+			t.Fatal("Expected checksum error, got nil")
+		}
+
+		if _, err := os.Stat(filepath.Join(cacheDir, cachedRegistryFile)); !os.IsNotExist(err) {
+			t.Errorf("Expected cache to be untouched")
+		}
+	})
+
+	t.Run("Falls back when offline", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		url := server.URL
+		server.Close()
+
+		result, err := SyncRegistry(SyncOptions{URL: url, CacheDir: t.TempDir()})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result.Status != SyncOffline || result.Source != "embedded" {
+			t.Errorf("Expected offline fallback to embedded registry, got %s/%s", result.Status, result.Source)
+		}
+	})
+
+	t.Run("Tampered cache is ignored", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(testRemoteRegistry))
+		}))
+		defer server.Close()
+
+		cacheDir := t.TempDir()
+		if _, err := SyncRegistry(SyncOptions{URL: server.URL, CacheDir: cacheDir}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(cacheDir, cachedRegistryFile), []byte(`{"providers": []}`), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := loadCachedRegistry(cacheDir); err == nil {
+			t.Errorf("Expected checksum error for tampered cache, got nil")
+		}
+	})
+}
@@ -0,0 +1,249 @@
+package providers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sharik709/bootstraper/util"
+)
+
+// maxVersionsResponseSize guards against unexpectedly large registry responses
+const maxVersionsResponseSize = 32 << 20
+
+// VersionSource names the package whose published releases are a provider's
+// versions, e.g. {"type": "npm", "package": "create-next-app"}
+type VersionSource struct {
+	// Type selects the resolver: npm, packagist, pypi, goproxy or pubdev
+	Type    string `json:"type"`
+	Package string `json:"package"`
+}
+
+// Releases lists the published versions of a package, newest first
+type Releases struct {
+	Versions []string `json:"versions"`
+	// Tags maps release channels such as "latest" to a version
+	Tags map[string]string `json:"tags,omitempty"`
+}
+
+// VersionResolver lists the releases of a package in one ecosystem
+type VersionResolver interface {
+	// DefaultURL is the base URL of the public registry
+	DefaultURL() string
+	// Releases fetches the releases of pkg from the registry at baseURL
+	Releases(client *http.Client, baseURL, pkg string) (*Releases, error)
+}
+
+// versionResolvers holds the resolvers by VersionSource type
+var versionResolvers = make(map[string]VersionResolver)
+
+// RegisterVersionResolver makes a resolver available to VersionSource type name
+func RegisterVersionResolver(name string, r VersionResolver) {
+	versionResolvers[name] = r
+}
+
+// ReleaseSourcer is implemented by providers whose versions are discovered
+// from a package registry
+type ReleaseSourcer interface {
+	ReleaseSource() *VersionSource
+}
+
+// VersionOptions configures ListReleases
+type VersionOptions struct {
+	// BaseURLs overrides the registry base URL per resolver type
+	BaseURLs map[string]string
+	// Client is the HTTP client to use; a client with a timeout is used if nil
+	Client *http.Client
+}
+
+// NewVersionOptions returns the version options from the user configuration
+func NewVersionOptions(config *util.Config) VersionOptions {
+	return VersionOptions{BaseURLs: config.VersionSources}
+}
+
+// ListReleases returns the releases of p. Providers with a version source
+// are looked up live; others report their static SupportedVersions.
+func ListReleases(p Provider, opts VersionOptions) (*Releases, error) {
+	var source *VersionSource
+	if rs, ok := p.(ReleaseSourcer); ok {
+		source = rs.ReleaseSource()
+	}
+	if source == nil {
+		return &Releases{Versions: p.SupportedVersions()}, nil
+	}
+
+	resolver, ok := versionResolvers[source.Type]
+	if !ok {
+		return nil, fmt.Errorf("%s: unknown version source type %q", p.Name(), source.Type)
+	}
+
+	baseURL := opts.BaseURLs[source.Type]
+	if baseURL == "" {
+		baseURL = resolver.DefaultURL()
+	}
+
+	client := opts.Client
+	if client == nil {
+		client = &http.Client{Timeout: 15 * time.Second}
+	}
+
+	releases, err := resolver.Releases(client, strings.TrimRight(baseURL, "/"), source.Package)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s versions from %s: %v", source.Package, baseURL, err)
+	}
+	sortVersions(releases.Versions)
+	return releases, nil
+}
+
+// Stable returns the versions that are semver releases, without prereleases
+func (r *Releases) Stable() []string {
+	var stable []string
+	for _, version := range r.Versions {
+		if isStable(version) {
+			stable = append(stable, version)
+		}
+	}
+	return stable
+}
+
+// Match returns the newest version matching requested, which is a tag, an
+// exact version or a semver range. Prereleases only match exact requests or
+// tags.
+func (r *Releases) Match(requested string) (string, error) {
+	if version, ok := r.Tags[requested]; ok {
+		return version, nil
+	}
+
+	for _, version := range r.Versions {
+		if version == requested {
+			return version, nil
+		}
+	}
+
+	if want, err := util.ParseVersion(requested); err == nil && isExactVersion(requested) {
+		// An exact version matches regardless of a "v" prefix
+		for _, version := range r.Versions {
+			if v, err := util.ParseVersion(version); err == nil && v.Compare(want) == 0 {
+				return version, nil
+			}
+		}
+		return "", fmt.Errorf("version %s does not exist", requested)
+	}
+
+	constraint, err := util.ParseConstraint(requested)
+	if err != nil {
+		return "", fmt.Errorf("unknown version %q: expected a version, a range or a tag", requested)
+	}
+	for _, version := range r.Versions {
+		if !isStable(version) {
+			continue
+		}
+		if v, err := util.ParseVersion(version); err == nil && constraint.Check(v) {
+			return version, nil
+		}
+	}
+	return "", fmt.Errorf("no version matches %s", requested)
+}
+
+// ResolveVersion returns the concrete version to use for requested. An
+// empty request or "latest" resolves to the "latest" tag, or the newest
+// stable version when there is no such tag. The result is empty when no
+// versions are known.
+func ResolveVersion(releases *Releases, requested string) (string, error) {
+	if requested == "" || requested == "latest" {
+		if version, ok := releases.Tags["latest"]; ok {
+			return version, nil
+		}
+		if stable := releases.Stable(); len(stable) > 0 {
+			return stable[0], nil
+		}
+		return requested, nil
+	}
+
+	if len(releases.Versions) == 0 {
+		return requested, nil
+	}
+	return releases.Match(requested)
+}
+
+// sortVersions sorts versions newest first. Versions that are not valid
+// semver sort last, in their original order.
+func sortVersions(versions []string) {
+	parsed := make(map[string]util.Version, len(versions))
+	for _, version := range versions {
+		if v, err := util.ParseVersion(version); err == nil {
+			parsed[version] = v
+		}
+	}
+
+	sort.SliceStable(versions, func(i, j int) bool {
+		vi, iok := parsed[versions[i]]
+		vj, jok := parsed[versions[j]]
+		if iok && jok {
+			return vi.Compare(vj) > 0
+		}
+		return iok && !jok
+	})
+}
+
+// isExactVersion reports whether s has all three version components, as
+// opposed to a partial version such as "14" that is treated as a range
+func isExactVersion(s string) bool {
+	core := strings.TrimPrefix(s, "v")
+	if i := strings.IndexAny(core, "-+"); i >= 0 {
+		core = core[:i]
+	}
+	return strings.Count(core, ".") == 2
+}
+
+// isStable reports whether version is a semver release without a prerelease
+func isStable(version string) bool {
+	v, err := util.ParseVersion(version)
+	return err == nil && v.Prerelease == ""
+}
+
+// getJSON fetches url into v
+func getJSON(client *http.Client, url string, accept string, v interface{}) error {
+	body, err := get(client, url, accept)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("invalid response from %s: %v", url, err)
+	}
+	return nil
+}
+
+// get fetches url and returns the response body
+func get(client *http.Client, url string, accept string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response from %s: %s", url, resp.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxVersionsResponseSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > maxVersionsResponseSize {
+		return nil, fmt.Errorf("response from %s exceeds %d bytes", url, maxVersionsResponseSize)
+	}
+	return body, nil
+}
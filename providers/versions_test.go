@@ -0,0 +1,161 @@
+package providers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// newVersionServer stands in for the npm, Packagist, PyPI, Go proxy and
+// pub.dev APIs
+func newVersionServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/npm/@angular/cli", func(w http.ResponseWriter, r *http.Request) {
+		// The registry expects the scope separator to be escaped
+		if r.URL.EscapedPath() != "/npm/@angular%2Fcli" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `{"dist-tags": {"latest": "17.1.0", "next": "18.0.0-rc.1"},
+			"versions": {"16.2.0": {}, "17.1.0": {}, "17.0.3": {}, "18.0.0-rc.1": {}}}`)
+	})
+	mux.HandleFunc("/packagist/p2/laravel/laravel.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"packages": {"laravel/laravel": [{"version": "v11.1.0"}, {"version": "v10.3.2"}, {"version": "v11.0.0"}]}}`)
+	})
+	mux.HandleFunc("/pypi/pypi/Django/json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"info": {"version": "5.0.2"},
+			"releases": {"4.2.9": [{}], "5.0.2": [{}], "5.0rc1": [{}], "1.0.1": []}}`)
+	})
+	mux.HandleFunc("/goproxy/github.com/!burnt!sushi/toml/@v/list", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "v1.2.1\nv1.3.2\nv0.4.0\n")
+	})
+	mux.HandleFunc("/pubdev/api/packages/http", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"latest": {"version": "1.2.0"}, "versions": [{"version": "1.1.2"}, {"version": "1.2.0"}]}`)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestListReleases(t *testing.T) {
+	server := newVersionServer(t)
+	opts := VersionOptions{BaseURLs: map[string]string{
+		"npm":       server.URL + "/npm",
+		"packagist": server.URL + "/packagist",
+		"pypi":      server.URL + "/pypi",
+		"goproxy":   server.URL + "/goproxy",
+		"pubdev":    server.URL + "/pubdev",
+	}}
+
+	cases := []struct {
+		source   VersionSource
+		expected []string
+		latest   string
+	}{
+		{VersionSource{"npm", "@angular/cli"}, []string{"18.0.0-rc.1", "17.1.0", "17.0.3", "16.2.0"}, "17.1.0"},
+		{VersionSource{"packagist", "laravel/laravel"}, []string{"v11.1.0", "v11.0.0", "v10.3.2"}, ""},
+		{VersionSource{"pypi", "Django"}, []string{"5.0.2", "4.2.9", "5.0rc1"}, "5.0.2"},
+		{VersionSource{"goproxy", "github.com/BurntSushi/toml"}, []string{"v1.3.2", "v1.2.1", "v0.4.0"}, ""},
+		{VersionSource{"pubdev", "http"}, []string{"1.2.0", "1.1.2"}, "1.2.0"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.source.Type, func(t *testing.T) {
+			source := c.source
+			def := &ProviderDefinition{ProviderName: "demo", VersionSource: &source}
+
+			releases, err := ListReleases(def, opts)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(releases.Versions, c.expected) {
+				t.Errorf("Expected versions %v, got %v", c.expected, releases.Versions)
+			}
+			if releases.Tags["latest"] != c.latest {
+				t.Errorf("Expected latest tag %q, got %q", c.latest, releases.Tags["latest"])
+			}
+		})
+	}
+
+	t.Run("Providers without a source use their static versions", func(t *testing.T) {
+		def := &ProviderDefinition{ProviderName: "demo", Versions: []string{"2.0", "1.0"}}
+		releases, err := ListReleases(def, opts)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(releases.Versions, []string{"2.0", "1.0"}) {
+			t.Errorf("Expected static versions, got %v", releases.Versions)
+		}
+	})
+
+	t.Run("Unknown packages are an error", func(t *testing.T) {
+		def := &ProviderDefinition{ProviderName: "demo", VersionSource: &VersionSource{"npm", "does-not-exist"}}
+		if _, err := ListReleases(def, opts); err == nil {
+			t.Error("Expected error, got nil")
+		}
+	})
+}
+
+func TestMatch(t *testing.T) {
+	releases := &Releases{
+		Versions: []string{"v11.1.0", "v11.0.0", "v10.3.2", "12.0.0-beta.1"},
+		Tags:     map[string]string{"beta": "12.0.0-beta.1"},
+	}
+
+	valid := map[string]string{
+		"11.0.0":        "v11.0.0",
+		"v10.3.2":       "v10.3.2",
+		"^10":           "v10.3.2",
+		"11.x":          "v11.1.0",
+		">=10 <11.1":    "v11.0.0",
+		"beta":          "12.0.0-beta.1",
+		"12.0.0-beta.1": "12.0.0-beta.1",
+	}
+	for requested, expected := range valid {
+		if version, err := releases.Match(requested); err != nil || version != expected {
+			t.Errorf("Expected %q to match %s, got %s (%v)", requested, expected, version, err)
+		}
+	}
+
+	for _, requested := range []string{"11.2.0", "^12", "canary", ">=13"} {
+		if _, err := releases.Match(requested); err == nil {
+			t.Errorf("Expected %q to be invalid, got nil", requested)
+		}
+	}
+}
+
+func TestResolveVersion(t *testing.T) {
+	releases := &Releases{Versions: []string{"12.0.0-rc.1", "11.1.0", "11.0.0", "10.3.2"}}
+
+	cases := map[string]string{
+		"":       "11.1.0",
+		"latest": "11.1.0",
+		"^10":    "10.3.2",
+		"11.0.0": "11.0.0",
+	}
+	for requested, expected := range cases {
+		version, err := ResolveVersion(releases, requested)
+		if err != nil {
+			t.Errorf("Unexpected error for %q: %v", requested, err)
+			continue
+		}
+		if version != expected {
+			t.Errorf("Expected %q to resolve to %s, got %s", requested, expected, version)
+		}
+	}
+
+	releases.Tags = map[string]string{"latest": "11.0.0"}
+	if version, _ := ResolveVersion(releases, ""); version != "11.0.0" {
+		t.Errorf("Expected the latest tag to win, got %s", version)
+	}
+
+	if version, _ := ResolveVersion(&Releases{}, ""); version != "" {
+		t.Errorf("Expected no version without releases, got %s", version)
+	}
+	if version, err := ResolveVersion(&Releases{}, "anything"); err != nil || version != "anything" {
+		t.Errorf("Expected any version to be kept without releases, got %s (%v)", version, err)
+	}
+}
@@ -0,0 +1,401 @@
+package templates
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// archiveCacheDir is the directory under the cache directory holding
+	// downloaded template archives
+	archiveCacheDir = "templates"
+
+	// maxArchiveSize guards against unexpectedly large downloads
+	maxArchiveSize = 512 << 20
+)
+
+// Archive formats understood by Extract
+const (
+	FormatTarGz = "tar.gz"
+	FormatZip   = "zip"
+)
+
+// IsArchive reports whether source is an http(s) URL of a template
+// archive rather than of a git repository
+func IsArchive(source string) bool {
+	return (strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://")) && !IsGit(source)
+}
+
+// ArchiveFormat returns the format of the archive at rawURL from its file
+// extension: .tar.gz, .tgz or .zip
+func ArchiveFormat(rawURL string) (string, error) {
+	name := rawURL
+	if u, err := url.Parse(rawURL); err == nil {
+		name = u.Path
+	}
+	name = strings.ToLower(name)
+
+	switch {
+	case strings.HasSuffix(name, ".tar.gz"), strings.HasSuffix(name, ".tgz"):
+		return FormatTarGz, nil
+	case strings.HasSuffix(name, ".zip"):
+		return FormatZip, nil
+	}
+	return "", fmt.Errorf("unsupported template archive %s: expected a .tar.gz, .tgz or .zip URL", rawURL)
+}
+
+// FetchOptions configures FetchArchive
+type FetchOptions struct {
+	// URL of the archive
+	URL string
+	// CacheDir is where downloaded archives are kept
+	CacheDir string
+	// SHA256 optionally pins the expected checksum of the archive
+	SHA256 string
+	// Client is the HTTP client to use; a client with a timeout is used if nil
+	Client *http.Client
+}
+
+// FetchArchive downloads the archive at opts.URL into the cache directory
+// and returns its path. An archive pinned by checksum is only downloaded
+// when the cached copy is missing or does not match; a checksum mismatch
+// after downloading is an error and nothing is cached.
+func FetchArchive(opts FetchOptions) (string, error) {
+	if opts.CacheDir == "" {
+		return "", errors.New("cache directory is not configured")
+	}
+	format, err := ArchiveFormat(opts.URL)
+	if err != nil {
+		return "", err
+	}
+
+	dir := filepath.Join(opts.CacheDir, archiveCacheDir)
+	cached := filepath.Join(dir, sha256Hex([]byte(opts.URL))[:16]+"."+format)
+
+	if opts.SHA256 != "" {
+		if checksum, err := fileSHA256(cached); err == nil && strings.EqualFold(checksum, opts.SHA256) {
+			return cached, nil
+		}
+	}
+
+	client := opts.Client
+	if client == nil {
+		client = &http.Client{Timeout: 5 * time.Minute}
+	}
+
+	resp, err := client.Get(opts.URL)
+	if err != nil {
+		return "", fmt.Errorf("failed to download %s: %v", opts.URL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to download %s: %s", opts.URL, resp.Status)
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create cache directory: %v", err)
+	}
+	tmp, err := os.CreateTemp(dir, "download-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+
+	hash := sha256.New()
+	n, err := io.Copy(io.MultiWriter(tmp, hash), io.LimitReader(resp.Body, maxArchiveSize+1))
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to download %s: %v", opts.URL, err)
+	}
+	if n > maxArchiveSize {
+		return "", fmt.Errorf("template archive at %s exceeds %d bytes", opts.URL, maxArchiveSize)
+	}
+
+	checksum := hex.EncodeToString(hash.Sum(nil))
+	if opts.SHA256 != "" && !strings.EqualFold(opts.SHA256, checksum) {
+		return "", fmt.Errorf("template checksum mismatch: expected %s, got %s", opts.SHA256, checksum)
+	}
+
+	if err := os.Rename(tmp.Name(), cached); err != nil {
+		return "", fmt.Errorf("failed to cache template archive: %v", err)
+	}
+	return cached, nil
+}
+
+// Extract unpacks the archive file in the given format into dst. Entry
+// names must be relative and stay inside dst; device files, FIFOs and
+// hard links are rejected. Symlinks are created after all other entries,
+// so no entry can be written through one, and must point inside dst.
+func Extract(archive, format, dst string) error {
+	x := &extractor{root: dst}
+	if err := os.MkdirAll(dst, 0755); err != nil {
+		return err
+	}
+
+	var err error
+	switch format {
+	case FormatTarGz:
+		err = x.tarGz(archive)
+	case FormatZip:
+		err = x.zip(archive)
+	default:
+		err = fmt.Errorf("unsupported archive format %q", format)
+	}
+	if err != nil {
+		return err
+	}
+	return x.links()
+}
+
+// CopyArchive extracts the archive file into a temporary directory and
+// copies it into dst like a local template, without the archive's
+// top-level directory
+func CopyArchive(archive, format, dst string) error {
+	tmp, err := os.MkdirTemp("", "bt-template-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	if err := Extract(archive, format, tmp); err != nil {
+		return err
+	}
+	root, err := StripTopLevel(tmp)
+	if err != nil {
+		return err
+	}
+	return CopyDir(root, dst)
+}
+
+// StripTopLevel returns the single directory dir contains when it holds
+// nothing else, as archives usually wrap their files in one, or dir itself
+func StripTopLevel(dir string) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+	if len(entries) == 1 && entries[0].IsDir() {
+		return filepath.Join(dir, entries[0].Name()), nil
+	}
+	return dir, nil
+}
+
+// extractor writes archive entries below root
+type extractor struct {
+	root string
+	// symlinks are created once every other entry has been written
+	symlinks []symlinkEntry
+}
+
+type symlinkEntry struct {
+	name, target string
+}
+
+// path returns where the entry called name is extracted, rejecting names
+// that are absolute or escape the root
+func (x *extractor) path(name string) (string, error) {
+	name = strings.ReplaceAll(name, `\`, "/")
+	if strings.HasPrefix(name, "/") || filepath.IsAbs(name) || filepath.VolumeName(name) != "" {
+		return "", fmt.Errorf("archive entry %s has an absolute path", name)
+	}
+
+	clean := path.Clean(name)
+	if clean == ".." || strings.HasPrefix(clean, "../") {
+		return "", fmt.Errorf("archive entry %s escapes the extraction directory", name)
+	}
+	return filepath.Join(x.root, filepath.FromSlash(clean)), nil
+}
+
+// entry extracts one archive entry with the given mode from r
+func (x *extractor) entry(name string, mode fs.FileMode, r io.Reader, linkTarget string) error {
+	target, err := x.path(name)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case mode.IsDir():
+		return os.MkdirAll(target, 0755)
+	case mode&fs.ModeSymlink != 0:
+		x.symlinks = append(x.symlinks, symlinkEntry{name, linkTarget})
+		return nil
+	case mode.IsRegular():
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		out, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode.Perm())
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(out, r); err != nil {
+			out.Close()
+			return err
+		}
+		if err := out.Close(); err != nil {
+			return err
+		}
+		return os.Chmod(target, mode.Perm())
+	}
+	return fmt.Errorf("archive entry %s has unsupported type %v", name, mode.Type())
+}
+
+// links creates the symlinks collected while extracting. Each link is
+// checked once created, following the links before it, and must resolve
+// inside the root; a link that does not is removed.
+func (x *extractor) links() error {
+	root, err := filepath.EvalSymlinks(x.root)
+	if err != nil {
+		return err
+	}
+
+	for _, link := range x.symlinks {
+		// Paths are taken from the resolved root so that resolvePath only
+		// follows links inside the archive
+		name, _ := x.path(link.name)
+		rel, _ := filepath.Rel(x.root, name)
+		target := filepath.Join(root, rel)
+
+		if filepath.IsAbs(link.target) {
+			return fmt.Errorf("archive symlink %s points outside the archive: %s", link.name, link.target)
+		}
+		if parent, err := resolvePath(filepath.Dir(target)); err != nil || !within(root, parent) {
+			return fmt.Errorf("archive symlink %s is created outside the archive", link.name)
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		if err := os.Symlink(link.target, target); err != nil {
+			return err
+		}
+
+		if resolved, err := resolvePath(target); err != nil || !within(root, resolved) {
+			os.Remove(target)
+			return fmt.Errorf("archive symlink %s points outside the archive: %s", link.name, link.target)
+		}
+	}
+	return nil
+}
+
+func (x *extractor) tarGz(archive string) error {
+	f, err := os.Open(archive)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return fmt.Errorf("invalid tar.gz archive: %v", err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("invalid tar.gz archive: %v", err)
+		}
+
+		var mode fs.FileMode
+		switch header.Typeflag {
+		case tar.TypeDir:
+			mode = fs.ModeDir
+		case tar.TypeReg:
+			mode = fs.FileMode(header.Mode).Perm()
+		case tar.TypeSymlink:
+			mode = fs.ModeSymlink
+		case tar.TypeXGlobalHeader:
+			// pax metadata, e.g. the commit id in GitHub archives
+			continue
+		case tar.TypeLink:
+			return fmt.Errorf("archive entry %s is a hard link, which is not supported", header.Name)
+		default:
+			return fmt.Errorf("archive entry %s is a device or special file", header.Name)
+		}
+
+		if err := x.entry(header.Name, mode, tr, header.Linkname); err != nil {
+			return err
+		}
+	}
+}
+
+func (x *extractor) zip(archive string) error {
+	zr, err := zip.OpenReader(archive)
+	if err != nil {
+		return fmt.Errorf("invalid zip archive: %v", err)
+	}
+	defer zr.Close()
+
+	for _, file := range zr.File {
+		mode := file.Mode()
+		if mode&(fs.ModeDevice|fs.ModeCharDevice|fs.ModeNamedPipe|fs.ModeSocket) != 0 {
+			return fmt.Errorf("archive entry %s is a device or special file", file.Name)
+		}
+		if mode.IsRegular() && mode.Perm() == 0 {
+			// Archives made without Unix attributes
+			mode |= 0644
+		}
+
+		if err := x.zipEntry(file, mode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (x *extractor) zipEntry(file *zip.File, mode fs.FileMode) error {
+	rc, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("invalid zip entry %s: %v", file.Name, err)
+	}
+	defer rc.Close()
+
+	var linkTarget string
+	if mode&fs.ModeSymlink != 0 {
+		data, err := io.ReadAll(io.LimitReader(rc, 4096))
+		if err != nil {
+			return err
+		}
+		linkTarget = string(data)
+	}
+	return x.entry(file.Name, mode, rc, linkTarget)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// fileSHA256 returns the hex SHA-256 checksum of the file at path
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
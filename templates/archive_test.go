@@ -0,0 +1,262 @@
+package templates
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// archiveEntry describes one entry of a test archive
+type archiveEntry struct {
+	name     string
+	content  string
+	mode     int64
+	typeflag byte
+	link     string
+}
+
+func buildTarGz(t *testing.T, entries []archiveEntry) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for _, e := range entries {
+		typeflag := e.typeflag
+		if typeflag == 0 {
+			typeflag = tar.TypeReg
+		}
+		mode := e.mode
+		if mode == 0 {
+			mode = 0644
+		}
+		header := &tar.Header{Name: e.name, Mode: mode, Typeflag: typeflag, Linkname: e.link, Size: int64(len(e.content))}
+		if typeflag != tar.TypeReg {
+			header.Size = 0
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if typeflag == tar.TypeReg {
+			if _, err := tw.Write([]byte(e.content)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func buildZip(t *testing.T, entries []archiveEntry) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, e := range entries {
+		header := &zip.FileHeader{Name: e.name, Method: zip.Deflate}
+		header.SetMode(os.FileMode(0644))
+		if e.mode != 0 {
+			header.SetMode(os.FileMode(e.mode))
+		}
+		w, err := zw.CreateHeader(header)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(e.content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// newArchiveServer serves the archives keyed by path and counts requests
+func newArchiveServer(t *testing.T, archives map[string][]byte) (*httptest.Server, *int) {
+	t.Helper()
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		data, ok := archives[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(data)
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestFetchArchive(t *testing.T) {
+	tarGz := buildTarGz(t, []archiveEntry{
+		{name: "api-main/", typeflag: tar.TypeDir},
+		{name: "api-main/README.md", content: "# api\n"},
+		{name: "api-main/bin/run.sh", content: "#!/bin/sh\n", mode: 0755},
+		{name: "api-main/docs", typeflag: tar.TypeSymlink, link: "README.md"},
+	})
+	zipped := buildZip(t, []archiveEntry{
+		{name: "web/index.html", content: "<h1>web</h1>\n"},
+		{name: "web/.btignore", content: "*.log\n"},
+		{name: "web/debug.log", content: "noise\n"},
+	})
+	server, requests := newArchiveServer(t, map[string][]byte{
+		"/api.tar.gz": tarGz,
+		"/web.zip":    zipped,
+	})
+
+	t.Run("Extracts tar.gz without the top-level directory", func(t *testing.T) {
+		archive, err := FetchArchive(FetchOptions{URL: server.URL + "/api.tar.gz", CacheDir: t.TempDir()})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		dst := filepath.Join(t.TempDir(), "app")
+		if err := CopyArchive(archive, FormatTarGz, dst); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if data, err := os.ReadFile(filepath.Join(dst, "README.md")); err != nil || string(data) != "# api\n" {
+			t.Errorf("Expected README.md at the project root, got %q (%v)", data, err)
+		}
+		if info, err := os.Stat(filepath.Join(dst, "bin", "run.sh")); err != nil || info.Mode().Perm() != 0755 {
+			t.Errorf("Expected executable run.sh, got %v (%v)", info, err)
+		}
+		if link, err := os.Readlink(filepath.Join(dst, "docs")); err != nil || link != "README.md" {
+			t.Errorf("Expected docs -> README.md, got %q (%v)", link, err)
+		}
+	})
+
+	t.Run("Extracts zip and honors .btignore", func(t *testing.T) {
+		archive, err := FetchArchive(FetchOptions{URL: server.URL + "/web.zip", CacheDir: t.TempDir()})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		dst := filepath.Join(t.TempDir(), "app")
+		if err := CopyArchive(archive, FormatZip, dst); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if _, err := os.Stat(filepath.Join(dst, "index.html")); err != nil {
+			t.Errorf("Expected index.html at the project root: %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(dst, "debug.log")); !os.IsNotExist(err) {
+			t.Error("Expected debug.log to be ignored")
+		}
+	})
+
+	t.Run("Pinned archives are verified and cached", func(t *testing.T) {
+		cacheDir := t.TempDir()
+		opts := FetchOptions{URL: server.URL + "/api.tar.gz", CacheDir: cacheDir, SHA256: sha256Hex(tarGz)}
+
+		*requests = 0
+		for i := 0; i < 2; i++ {
+			if _, err := FetchArchive(opts); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		}
+		if *requests != 1 {
+			t.Errorf("Expected the cached archive to be reused, got %d requests", *requests)
+		}
+
+		opts.SHA256 = strings.Repeat("0", 64)
+		opts.CacheDir = t.TempDir()
+		_, err := FetchArchive(opts)
+		if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+			t.Errorf("Expected checksum mismatch, got %v", err)
+		}
+		if entries, _ := os.ReadDir(filepath.Join(opts.CacheDir, archiveCacheDir)); len(entries) != 0 {
+			t.Errorf("Expected nothing to be cached after a mismatch, got %d files", len(entries))
+		}
+	})
+
+	t.Run("Unsupported formats and missing archives are errors", func(t *testing.T) {
+		if _, err := FetchArchive(FetchOptions{URL: server.URL + "/api.rar", CacheDir: t.TempDir()}); err == nil {
+			t.Error("Expected error for unsupported format, got nil")
+		}
+		if _, err := FetchArchive(FetchOptions{URL: server.URL + "/missing.zip", CacheDir: t.TempDir()}); err == nil {
+			t.Error("Expected error for missing archive, got nil")
+		}
+	})
+}
+
+func TestExtractRejectsUnsafeEntries(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []archiveEntry
+		want    string
+	}{
+		{"Zip slip", []archiveEntry{{name: "app/../../evil.sh", content: "x"}}, "escapes"},
+		{"Absolute path", []archiveEntry{{name: "/etc/evil", content: "x"}}, "absolute"},
+		{"Device file", []archiveEntry{{name: "app/null", typeflag: tar.TypeChar}}, "device"},
+		{"Hard link", []archiveEntry{{name: "app/passwd", typeflag: tar.TypeLink, link: "/etc/passwd"}}, "hard link"},
+		{"Escaping symlink", []archiveEntry{{name: "app/etc", typeflag: tar.TypeSymlink, link: "../../etc"}}, "outside"},
+		{"Escape through another symlink", []archiveEntry{
+			{name: "b", typeflag: tar.TypeSymlink, link: "."},
+			{name: "a", typeflag: tar.TypeSymlink, link: "b/.."},
+		}, "outside"},
+		{"Symlink created through another symlink", []archiveEntry{
+			{name: "b", typeflag: tar.TypeSymlink, link: "."},
+			{name: "b/c", typeflag: tar.TypeSymlink, link: "../.."},
+		}, "outside"},
+		{"Write through symlink", []archiveEntry{
+			{name: "app/etc", typeflag: tar.TypeSymlink, link: "/etc"},
+			{name: "app/etc/evil", content: "x"},
+		}, "outside"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			archive := filepath.Join(t.TempDir(), "t.tar.gz")
+			if err := os.WriteFile(archive, buildTarGz(t, tt.entries), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			parent := t.TempDir()
+			err := Extract(archive, FormatTarGz, filepath.Join(parent, "out"))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %q, got %v", tt.want, err)
+			}
+			if _, err := os.Lstat(filepath.Join(parent, "evil.sh")); !os.IsNotExist(err) {
+				t.Error("Expected nothing to be written outside the extraction directory")
+			}
+		})
+	}
+
+	t.Run("Escape through another symlink below the top-level directory", func(t *testing.T) {
+		archive := filepath.Join(t.TempDir(), "t.tar.gz")
+		entries := []archiveEntry{
+			{name: "app/b", typeflag: tar.TypeSymlink, link: "."},
+			{name: "app/a", typeflag: tar.TypeSymlink, link: "b/.."},
+		}
+		if err := os.WriteFile(archive, buildTarGz(t, entries), 0644); err != nil {
+			t.Fatal(err)
+		}
+		err := CopyArchive(archive, FormatTarGz, filepath.Join(t.TempDir(), "proj"))
+		if err == nil || !strings.Contains(err.Error(), "outside") {
+			t.Errorf("Expected error containing %q, got %v", "outside", err)
+		}
+	})
+
+	t.Run("Zip slip in zip archives", func(t *testing.T) {
+		archive := filepath.Join(t.TempDir(), "t.zip")
+		if err := os.WriteFile(archive, buildZip(t, []archiveEntry{{name: "../evil.sh", content: "x"}}), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := Extract(archive, FormatZip, t.TempDir()); err == nil {
+			t.Error("Expected error, got nil")
+		}
+	})
+}
@@ -0,0 +1,238 @@
+package templates
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// CopyDir copies the template directory src into dst, which is created
+// if needed. File modes and symlinks are preserved, version control
+// metadata and paths matched by the template's ignore file are skipped.
+//
+// Symlinks are copied as links, never followed. A link whose target lies
+// outside src is rejected, so a template cannot expose files from the
+// machine it is copied on; absolute links into src are made relative.
+func CopyDir(src, dst string) error {
+	abs, err := filepath.Abs(src)
+	if err != nil {
+		return err
+	}
+	root, err := filepath.EvalSymlinks(abs)
+	if err != nil {
+		return fmt.Errorf("template directory %s: %v", src, err)
+	}
+	if info, err := os.Stat(root); err != nil {
+		return fmt.Errorf("template directory %s: %v", src, err)
+	} else if !info.IsDir() {
+		return fmt.Errorf("template %s is not a directory", src)
+	}
+
+	dst, err = filepath.Abs(dst)
+	if err != nil {
+		return err
+	}
+	if within(root, dst) {
+		return fmt.Errorf("cannot copy template %s into itself", src)
+	}
+
+	ignore, err := LoadIgnore(root)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %v", IgnoreFile, err)
+	}
+
+	// Directory modes are applied last so read-only directories can still
+	// be filled
+	type dirMode struct {
+		path string
+		mode fs.FileMode
+	}
+	var dirs []dirMode
+
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		if rel == "." {
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+			return nil
+		}
+
+		slashed := filepath.ToSlash(rel)
+		if skip(slashed, d, ignore) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		switch mode := info.Mode(); {
+		case mode.IsDir():
+			if err := os.Mkdir(target, 0755); err != nil && !os.IsExist(err) {
+				return err
+			}
+			dirs = append(dirs, dirMode{target, mode.Perm()})
+			return nil
+		case mode&fs.ModeSymlink != 0:
+			return copySymlink(root, path, target, slashed)
+		case mode.IsRegular():
+			return copyFile(path, target, mode.Perm())
+		default:
+			return fmt.Errorf("unsupported file type in template: %s", slashed)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	for i := len(dirs) - 1; i >= 0; i-- {
+		if err := os.Chmod(dirs[i].path, dirs[i].mode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// skip reports whether the template entry at the slash-separated path rel
+// is left out of the copy
+func skip(rel string, d fs.DirEntry, ignore *Ignore) bool {
+	if rel == IgnoreFile {
+		return true
+	}
+	if d.IsDir() && vcsDirs[d.Name()] {
+		return true
+	}
+	return ignore.Match(rel, d.IsDir())
+}
+
+// copyFile copies the regular file src to dst with the given permissions
+func copyFile(src, dst string, perm fs.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+
+	// The umask may have dropped bits from perm
+	return os.Chmod(dst, perm)
+}
+
+// copySymlink recreates the symlink at path under dst, rejecting links
+// that point outside root once every symlink on the way is followed.
+// Absolute links into the template are made relative.
+func copySymlink(root, path, dst, rel string) error {
+	link, err := os.Readlink(path)
+	if err != nil {
+		return err
+	}
+
+	resolved, err := resolvePath(path)
+	if err != nil {
+		return fmt.Errorf("symlink %s: %v", rel, err)
+	}
+	if !within(root, resolved) {
+		return fmt.Errorf("symlink %s points outside the template: %s", rel, link)
+	}
+
+	if filepath.IsAbs(link) {
+		link, err = filepath.Rel(filepath.Dir(path), resolved)
+		if err != nil {
+			return err
+		}
+	}
+	return os.Symlink(link, dst)
+}
+
+// maxLinks bounds the symlinks followed by resolvePath, as the kernel does
+const maxLinks = 40
+
+// resolvePath returns the absolute path that path leads to, following
+// symlinks component by component so that ".." after a link applies to the
+// link's target rather than to the name. Components that do not exist are
+// kept as they are, so dangling links resolve to the path they name.
+func resolvePath(path string) (string, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+
+	volume := filepath.VolumeName(path)
+	sep := string(filepath.Separator)
+	resolved := volume + sep
+	rest := strings.Split(path[len(volume):], sep)
+	links := 0
+
+	for len(rest) > 0 {
+		part := rest[0]
+		rest = rest[1:]
+
+		switch part {
+		case "", ".":
+			continue
+		case "..":
+			resolved = filepath.Dir(resolved)
+			continue
+		}
+
+		next := filepath.Join(resolved, part)
+		info, err := os.Lstat(next)
+		if err != nil || info.Mode()&fs.ModeSymlink == 0 {
+			resolved = next
+			continue
+		}
+
+		links++
+		if links > maxLinks {
+			return "", fmt.Errorf("too many levels of symbolic links in %s", path)
+		}
+		target, err := os.Readlink(next)
+		if err != nil {
+			return "", err
+		}
+		if filepath.IsAbs(target) {
+			volume = filepath.VolumeName(target)
+			resolved = volume + sep
+			target = target[len(volume):]
+		}
+		rest = append(strings.Split(target, sep), rest...)
+	}
+	return resolved, nil
+}
+
+// within reports whether path is root or inside it
+func within(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return false
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
+}
@@ -0,0 +1,147 @@
+package templates
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeTree creates the files in dir, keyed by slash-separated path
+func writeTree(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestCopyDir(t *testing.T) {
+	t.Run("Copies files, modes and symlinks", func(t *testing.T) {
+		src := t.TempDir()
+		writeTree(t, src, map[string]string{
+			"README.md":        "# template\n",
+			"bin/run.sh":       "#!/bin/sh\n",
+			".git/HEAD":        "ref: refs/heads/main\n",
+			"debug.log":        "noise\n",
+			"src/.svn/entries": "",
+			".btignore":        "*.log\n",
+		})
+		if err := os.Chmod(filepath.Join(src, "bin", "run.sh"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Symlink("README.md", filepath.Join(src, "link.md")); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Symlink(filepath.Join(src, "bin"), filepath.Join(src, "tools")); err != nil {
+			t.Fatal(err)
+		}
+
+		dst := filepath.Join(t.TempDir(), "app")
+		if err := CopyDir(src, dst); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if data, err := os.ReadFile(filepath.Join(dst, "README.md")); err != nil || string(data) != "# template\n" {
+			t.Errorf("Expected README.md to be copied, got %q (%v)", data, err)
+		}
+
+		info, err := os.Stat(filepath.Join(dst, "bin", "run.sh"))
+		if err != nil {
+			t.Fatalf("Expected run.sh to be copied: %v", err)
+		}
+		if info.Mode().Perm() != 0755 {
+			t.Errorf("Expected mode 0755, got %v", info.Mode().Perm())
+		}
+
+		if link, err := os.Readlink(filepath.Join(dst, "link.md")); err != nil || link != "README.md" {
+			t.Errorf("Expected link.md -> README.md, got %q (%v)", link, err)
+		}
+		if link, err := os.Readlink(filepath.Join(dst, "tools")); err != nil || link != "bin" {
+			t.Errorf("Expected absolute link to be made relative, got %q (%v)", link, err)
+		}
+
+		for _, name := range []string{".git", "debug.log", "src/.svn", ".btignore"} {
+			if _, err := os.Lstat(filepath.Join(dst, filepath.FromSlash(name))); !os.IsNotExist(err) {
+				t.Errorf("Expected %s to be skipped", name)
+			}
+		}
+	})
+
+	t.Run("Rejects symlinks escaping the template", func(t *testing.T) {
+		for _, target := range []string{"../outside", "/etc/passwd"} {
+			src := t.TempDir()
+			writeTree(t, src, map[string]string{"README.md": ""})
+			if err := os.Symlink(target, filepath.Join(src, "escape")); err != nil {
+				t.Fatal(err)
+			}
+
+			err := CopyDir(src, filepath.Join(t.TempDir(), "app"))
+			if err == nil || !strings.Contains(err.Error(), "outside the template") {
+				t.Errorf("Expected error for link to %s, got %v", target, err)
+			}
+		}
+	})
+
+	t.Run("Rejects symlinks escaping through other symlinks", func(t *testing.T) {
+		src := t.TempDir()
+		writeTree(t, src, map[string]string{"README.md": ""})
+		if err := os.Symlink(".", filepath.Join(src, "b")); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Symlink("b/..", filepath.Join(src, "a")); err != nil {
+			t.Fatal(err)
+		}
+
+		err := CopyDir(src, filepath.Join(t.TempDir(), "app"))
+		if err == nil || !strings.Contains(err.Error(), "outside the template") {
+			t.Errorf("Expected error for a -> b/.., got %v", err)
+		}
+	})
+
+	t.Run("Rejects symlink loops", func(t *testing.T) {
+		src := t.TempDir()
+		if err := os.Symlink("loop", filepath.Join(src, "loop")); err != nil {
+			t.Fatal(err)
+		}
+		if err := CopyDir(src, filepath.Join(t.TempDir(), "app")); err == nil {
+			t.Error("Expected error, got nil")
+		}
+	})
+
+	t.Run("Rejects copying into itself", func(t *testing.T) {
+		src := t.TempDir()
+		if err := CopyDir(src, filepath.Join(src, "app")); err == nil {
+			t.Error("Expected error, got nil")
+		}
+	})
+}
+
+func TestLocalPath(t *testing.T) {
+	t.Setenv("HOME", "/home/dev")
+
+	tests := map[string]string{
+		"file:///srv/templates/go%20api": "/srv/templates/go api",
+		"file://localhost/srv/tpl":       "/srv/tpl",
+		"~/templates/web":                "/home/dev/templates/web",
+	}
+	for source, want := range tests {
+		got, err := LocalPath(source)
+		if err != nil || got != want {
+			t.Errorf("Expected %s to be %s, got %s (%v)", source, want, got, err)
+		}
+	}
+
+	if _, err := LocalPath("file://server/share"); err == nil {
+		t.Error("Expected error for remote file URL, got nil")
+	}
+
+	if IsLocal("github:user/repo") || IsLocal("https://example.com/t.zip") || !IsLocal("./tpl") {
+		t.Error("Expected only paths and file URLs to be local")
+	}
+}
@@ -0,0 +1,244 @@
+package templates
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// gitHosts maps source shorthands to their hosts, e.g. github:owner/repo
+var gitHosts = map[string]string{
+	"github:":    "github.com",
+	"gitlab:":    "gitlab.com",
+	"bitbucket:": "bitbucket.org",
+}
+
+// scpLike matches ssh sources such as git@github.com:org/repo
+var scpLike = regexp.MustCompile(`^[\w.-]+@[\w.-]+:[^/]`)
+
+// commitID matches full or abbreviated commit ids
+var commitID = regexp.MustCompile(`^[0-9a-fA-F]{7,40}$`)
+
+// GitSource is a template in a git repository. Sources are written as
+// <repo>[@ref][//subdir], e.g. github:org/templates@v2//go-service
+type GitSource struct {
+	// URL is what git clones, e.g. https://github.com/org/templates.git
+	URL string
+	// Ref is a branch, tag or commit; empty means the default branch
+	Ref string
+	// Subdir is the template directory inside the repository
+	Subdir string
+}
+
+// IsGit reports whether source names a git repository: a github:, gitlab:
+// or bitbucket: shorthand, an ssh or git:// URL, an http(s) URL of a .git
+// repository or a file:// URL of a bare repository
+func IsGit(source string) bool {
+	for prefix := range gitHosts {
+		if strings.HasPrefix(source, prefix) {
+			return true
+		}
+	}
+	if scpLike.MatchString(source) {
+		return true
+	}
+
+	repo, _, _ := splitGitSource(source)
+	switch {
+	case strings.HasPrefix(source, "ssh://"), strings.HasPrefix(source, "git://"):
+		return true
+	case strings.HasPrefix(source, "http://"), strings.HasPrefix(source, "https://"):
+		return strings.HasSuffix(repo, ".git")
+	case strings.HasPrefix(source, "file://"):
+		return strings.HasSuffix(repo, ".git") || isBareRepo(strings.TrimPrefix(repo, "file://"))
+	}
+	return false
+}
+
+// ParseGitSource splits a git source into the repository URL, ref and
+// subdirectory
+func ParseGitSource(source string) (GitSource, error) {
+	repo, ref, subdir := splitGitSource(source)
+
+	for prefix, host := range gitHosts {
+		if strings.HasPrefix(repo, prefix) {
+			name := strings.Trim(strings.TrimPrefix(repo, prefix), "/")
+			if !strings.Contains(name, "/") {
+				return GitSource{}, fmt.Errorf("invalid template source %s: expected %sowner/repo", source, prefix)
+			}
+			repo = "https://" + host + "/" + strings.TrimSuffix(name, ".git") + ".git"
+		}
+	}
+
+	if subdir != "" {
+		clean := path.Clean(subdir)
+		if path.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, "../") {
+			return GitSource{}, fmt.Errorf("invalid template source %s: subdirectory %s is outside the repository", source, subdir)
+		}
+		subdir = clean
+	}
+
+	if err := checkRef(ref); err != nil {
+		return GitSource{}, fmt.Errorf("invalid template source %s: %v", source, err)
+	}
+
+	if strings.HasPrefix(repo, "file://") {
+		u, err := url.Parse(repo)
+		if err != nil {
+			return GitSource{}, fmt.Errorf("invalid template source %s: %v", source, err)
+		}
+		if u.Host != "" && u.Host != "localhost" {
+			return GitSource{}, fmt.Errorf("invalid template source %s: file URLs must name a local absolute path", source)
+		}
+	}
+
+	return GitSource{URL: repo, Ref: ref, Subdir: subdir}, nil
+}
+
+// splitGitSource splits source into the repository, the ref after "@" and
+// the subdirectory after "//". Both are looked for in the repository path
+// only, so user names in ssh and http URLs are not mistaken for refs.
+func splitGitSource(source string) (repo, ref, subdir string) {
+	start := 0
+	if i := strings.Index(source, "://"); i >= 0 {
+		// Skip the scheme and, except for file URLs, the host
+		start = i + 3
+		if !strings.HasPrefix(source, "file://") {
+			if j := strings.Index(source[start:], "/"); j >= 0 {
+				start += j
+			} else {
+				start = len(source)
+			}
+		}
+	} else if i := strings.Index(source, ":"); i >= 0 {
+		start = i + 1
+	}
+
+	repo = source
+	if i := strings.Index(source[start:], "//"); i >= 0 {
+		subdir = source[start+i+2:]
+		repo = source[:start+i]
+	}
+	if i := strings.Index(repo[start:], "@"); i >= 0 {
+		ref = repo[start+i+1:]
+		repo = repo[:start+i]
+	}
+	return repo, ref, subdir
+}
+
+// checkRef rejects refs git would not accept as a branch or tag name, and
+// any that could be mistaken for an option. Commit ids are always allowed.
+func checkRef(ref string) error {
+	if strings.HasPrefix(ref, "-") {
+		return fmt.Errorf("invalid ref %s", ref)
+	}
+	if ref == "" || commitID.MatchString(ref) {
+		return nil
+	}
+	if _, err := exec.LookPath("git"); err != nil {
+		return errors.New("git is not installed")
+	}
+	if err := exec.Command("git", "check-ref-format", "--allow-onelevel", ref).Run(); err != nil {
+		return fmt.Errorf("invalid ref %s", ref)
+	}
+	return nil
+}
+
+// isBareRepo reports whether dir looks like a bare git repository
+func isBareRepo(dir string) bool {
+	for _, name := range []string{"HEAD", "objects", "refs"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Clone fetches the source into dir with a shallow clone of its ref and
+// returns the commit it checked out. Commits that cannot be fetched
+// directly, such as abbreviated ids, fall back to fetching full history.
+func (s GitSource) Clone(dir string) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	if _, err := git(dir, "init", "--quiet"); err != nil {
+		return "", err
+	}
+	if _, err := git(dir, "remote", "add", "--end-of-options", "origin", s.URL); err != nil {
+		return "", err
+	}
+
+	ref := s.Ref
+	if ref == "" {
+		ref = "HEAD"
+	}
+
+	if _, err := git(dir, "fetch", "--quiet", "--depth", "1", "--end-of-options", "origin", ref); err != nil {
+		if !commitID.MatchString(ref) {
+			return "", fmt.Errorf("failed to fetch %s from %s: %v", ref, s.URL, err)
+		}
+		if _, err := git(dir, "fetch", "--quiet", "--end-of-options", "origin"); err != nil {
+			return "", fmt.Errorf("failed to fetch %s: %v", s.URL, err)
+		}
+		if _, err := git(dir, "checkout", "--quiet", "--detach", ref); err != nil {
+			return "", fmt.Errorf("commit %s not found in %s", ref, s.URL)
+		}
+	} else if _, err := git(dir, "checkout", "--quiet", "--detach", "FETCH_HEAD"); err != nil {
+		return "", err
+	}
+
+	return git(dir, "rev-parse", "HEAD")
+}
+
+// CopyGit clones the source into a temporary directory and copies its
+// subdirectory, or the whole repository, into dst like a local template.
+// It returns the commit the project was created from.
+func CopyGit(source GitSource, dst string) (string, error) {
+	tmp, err := os.MkdirTemp("", "bt-template-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tmp)
+
+	commit, err := source.Clone(tmp)
+	if err != nil {
+		return "", err
+	}
+
+	root := tmp
+	if source.Subdir != "" {
+		root = filepath.Join(tmp, filepath.FromSlash(source.Subdir))
+		if info, err := os.Stat(root); err != nil || !info.IsDir() {
+			return "", fmt.Errorf("directory %s not found in %s", source.Subdir, source.URL)
+		}
+	}
+
+	if err := CopyDir(root, dst); err != nil {
+		return "", err
+	}
+	return commit, nil
+}
+
+// git runs a git command in dir without prompting for credentials and
+// returns its trimmed output
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+
+	out, err := cmd.CombinedOutput()
+	output := strings.TrimSpace(string(out))
+	if err != nil {
+		if output != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], output)
+		}
+		return "", fmt.Errorf("git %s: %v", args[0], err)
+	}
+	return output, nil
+}
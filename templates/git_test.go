@@ -0,0 +1,181 @@
+package templates
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseGitSource(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	tests := []struct {
+		source string
+		want   GitSource
+	}{
+		{"github:org/repo", GitSource{URL: "https://github.com/org/repo.git"}},
+		{"gitlab:group/sub/repo@v1.2.0", GitSource{URL: "https://gitlab.com/group/sub/repo.git", Ref: "v1.2.0"}},
+		{"bitbucket:team/repo//web", GitSource{URL: "https://bitbucket.org/team/repo.git", Subdir: "web"}},
+		{"git@github.com:org/templates.git@feature/x//go/api", GitSource{URL: "git@github.com:org/templates.git", Ref: "feature/x", Subdir: "go/api"}},
+		{"ssh://git@git.example.com/org/repo.git@main", GitSource{URL: "ssh://git@git.example.com/org/repo.git", Ref: "main"}},
+		{"https://user@git.example.com/org/repo.git@0a1b2c3//svc", GitSource{URL: "https://user@git.example.com/org/repo.git", Ref: "0a1b2c3", Subdir: "svc"}},
+		{"file:///srv/templates.git@v2//api", GitSource{URL: "file:///srv/templates.git", Ref: "v2", Subdir: "api"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			if !IsGit(tt.source) {
+				t.Fatalf("Expected %s to be a git source", tt.source)
+			}
+			got, err := ParseGitSource(tt.source)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+
+	t.Run("Other sources are not git", func(t *testing.T) {
+		for _, source := range []string{"/srv/templates/web", "https://example.com/t.tar.gz", "file:///srv/templates/web"} {
+			if IsGit(source) {
+				t.Errorf("Expected %s not to be a git source", source)
+			}
+		}
+	})
+
+	t.Run("Refs cannot be options", func(t *testing.T) {
+		for _, source := range []string{
+			"file:///tmp/repo.git@--upload-pack=touch /tmp/pwned;git-upload-pack",
+			"github:org/repo@-b",
+		} {
+			_, err := ParseGitSource(source)
+			if err == nil || !strings.Contains(err.Error(), "invalid ref") {
+				t.Errorf("Expected invalid ref error for %s, got %v", source, err)
+			}
+		}
+	})
+
+	t.Run("Invalid sources", func(t *testing.T) {
+		for _, source := range []string{
+			"github:repo",
+			"github:org/repo//../../etc",
+			"file://server/repo.git",
+			"github:org/repo@bad..ref",
+		} {
+			if _, err := ParseGitSource(source); err == nil {
+				t.Errorf("Expected error for %s, got nil", source)
+			}
+		}
+	})
+}
+
+// newBareRepo creates a bare repository with two commits, a v1 tag on the
+// first and a templates/api directory, and returns its path and commits
+func newBareRepo(t *testing.T) (string, []string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_AUTHOR_NAME", "test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	work := t.TempDir()
+	run := func(args ...string) string {
+		t.Helper()
+		out, err := git(work, args...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return out
+	}
+
+	run("init", "--quiet", "--initial-branch", "main")
+	writeTree(t, work, map[string]string{"README.md": "v1\n", "templates/api/main.go": "package main\n"})
+	run("add", ".")
+	run("commit", "--quiet", "-m", "first")
+	run("tag", "v1")
+	first := run("rev-parse", "HEAD")
+
+	writeTree(t, work, map[string]string{"README.md": "v2\n"})
+	run("commit", "--quiet", "-am", "second")
+	second := run("rev-parse", "HEAD")
+
+	bare := filepath.Join(t.TempDir(), "templates.git")
+	if _, err := git(work, "clone", "--quiet", "--bare", work, bare); err != nil {
+		t.Fatal(err)
+	}
+	return bare, []string{first, second}
+}
+
+func TestCopyGit(t *testing.T) {
+	bare, commits := newBareRepo(t)
+
+	tests := []struct {
+		name   string
+		source string
+		commit string
+		readme string
+	}{
+		{"Default branch", "file://" + bare, commits[1], "v2\n"},
+		{"Tag", "file://" + bare + "@v1", commits[0], "v1\n"},
+		{"Branch", "file://" + bare + "@main", commits[1], "v2\n"},
+		{"Full commit", "file://" + bare + "@" + commits[0], commits[0], "v1\n"},
+		{"Abbreviated commit", "file://" + bare + "@" + commits[0][:10], commits[0], "v1\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source, err := ParseGitSource(tt.source)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			dst := filepath.Join(t.TempDir(), "app")
+			commit, err := CopyGit(source, dst)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if commit != tt.commit {
+				t.Errorf("Expected commit %s, got %s", tt.commit, commit)
+			}
+			if data, _ := os.ReadFile(filepath.Join(dst, "README.md")); string(data) != tt.readme {
+				t.Errorf("Expected README.md %q, got %q", tt.readme, data)
+			}
+			if _, err := os.Stat(filepath.Join(dst, ".git")); !os.IsNotExist(err) {
+				t.Error("Expected .git to be skipped")
+			}
+		})
+	}
+
+	t.Run("Subdirectory", func(t *testing.T) {
+		source, _ := ParseGitSource("file://" + bare + "@v1//templates/api")
+		dst := filepath.Join(t.TempDir(), "app")
+		if _, err := CopyGit(source, dst); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(dst, "main.go")); err != nil {
+			t.Errorf("Expected main.go at the project root: %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(dst, "README.md")); !os.IsNotExist(err) {
+			t.Error("Expected only the subdirectory to be copied")
+		}
+	})
+
+	t.Run("Missing refs and subdirectories are errors", func(t *testing.T) {
+		for _, s := range []string{"@no-such-branch", "//no/such/dir"} {
+			source, _ := ParseGitSource("file://" + bare + s)
+			if _, err := CopyGit(source, filepath.Join(t.TempDir(), "app")); err == nil {
+				t.Errorf("Expected error for %s, got nil", s)
+			}
+		}
+	})
+}
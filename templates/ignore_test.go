@@ -0,0 +1,55 @@
+package templates
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIgnore(t *testing.T) {
+	rules := `# build output
+*.log
+/dist
+node_modules/
+docs/**/*.tmp
+!keep.log
+secret?.txt
+\#literal
+build/**
+`
+	ignore, err := ParseIgnore(strings.NewReader(rules))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		path    string
+		isDir   bool
+		ignored bool
+	}{
+		{"app.log", false, true},
+		{"logs/app.log", false, true},
+		{"keep.log", false, false},
+		{"dist", true, true},
+		{"src/dist", true, false},
+		{"node_modules", true, true},
+		{"src/node_modules", true, true},
+		{"node_modules", false, false},
+		{"docs/a.tmp", false, true},
+		{"docs/a/b/c.tmp", false, true},
+		{"a.tmp", false, false},
+		{"secret1.txt", false, true},
+		{"secret12.txt", false, false},
+		{"#literal", false, true},
+		{"build/out/main", false, true},
+		{"build", true, false},
+		{"main.go", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := ignore.Match(tt.path, tt.isDir); got != tt.ignored {
+				t.Errorf("Expected Match(%q, %v) to be %v, got %v", tt.path, tt.isDir, tt.ignored, got)
+			}
+		})
+	}
+}
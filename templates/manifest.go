@@ -0,0 +1,504 @@
+package templates
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/sharik709/bootstraper/providers"
+	"github.com/sharik709/bootstraper/util"
+	"gopkg.in/yaml.v3"
+)
+
+// ManifestFiles are the manifest names looked for at a template root, in
+// order of preference
+var ManifestFiles = []string{"bootstraper.json", "bootstraper.yaml", "bootstraper.yml"}
+
+// Variable types
+const (
+	VarString = "string"
+	VarBool   = "bool"
+	VarInt    = "int"
+	VarEnum   = "enum"
+)
+
+// Manifest describes a template's variables and conditional files:
+//
+//	{
+//	  "variables": [
+//	    {"name": "service", "prompt": "Service name", "default": "{{kebab .Name}}", "pattern": "^[a-z-]+$"},
+//	    {"name": "database", "choices": ["postgres", "mysql", "none"], "default": "postgres"},
+//	    {"name": "docker", "type": "bool", "default": true},
+//	    {"name": "module", "computed": "github.com/example/{{.service}}"}
+//	  ],
+//	  "files": [{"path": "docker/", "when": ".docker"}],
+//	  "copyOnly": ["assets/**"]
+//	}
+//
+// Every text file is rendered unless it matches CopyOnly. Templates whose
+// files use "{{" for something else, such as Vue, Helm or GitHub Actions
+// files, list the files to render in Render instead.
+type Manifest struct {
+	// Variables are resolved in order, so defaults and computed values can
+	// use the variables before them
+	Variables []Variable `json:"variables" yaml:"variables"`
+	// Files are included only when their condition holds
+	Files []FileRule `json:"files,omitempty" yaml:"files,omitempty"`
+	// CopyOnly lists files, in gitignore syntax, copied without rendering
+	CopyOnly []string `json:"copyOnly,omitempty" yaml:"copyOnly,omitempty"`
+	// Render lists the only files, in gitignore syntax, whose contents are
+	// rendered; all text files are rendered if it is empty
+	Render []string `json:"render,omitempty" yaml:"render,omitempty"`
+
+	// file is the manifest's name in the template
+	file string
+}
+
+// Variable is a value asked for when the template is used, or computed
+// from other variables
+type Variable struct {
+	Name string `json:"name" yaml:"name"`
+	// Type is string (default), bool, int or enum
+	Type string `json:"type,omitempty" yaml:"type,omitempty"`
+	// Prompt is the question asked for the variable, its name if empty
+	Prompt string `json:"prompt,omitempty" yaml:"prompt,omitempty"`
+	// Default is a template rendered with the variables before it
+	Default interface{} `json:"default,omitempty" yaml:"default,omitempty"`
+	// Pattern is a regular expression string values must match
+	Pattern string `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+	// Choices are the values allowed for an enum
+	Choices []string `json:"choices,omitempty" yaml:"choices,omitempty"`
+	// Computed is a template for the value; computed variables are not asked
+	Computed string `json:"computed,omitempty" yaml:"computed,omitempty"`
+	// Required variables must have a non-empty value
+	Required bool `json:"required,omitempty" yaml:"required,omitempty"`
+
+	pattern *regexp.Regexp
+}
+
+// FileRule includes the files matching Path only when When holds
+type FileRule struct {
+	// Path matches files or directories in gitignore syntax, e.g. "docker/"
+	Path string `json:"path" yaml:"path"`
+	// When is a template expression, with or without braces, e.g.
+	// ".docker" or "eq .database \"postgres\""
+	When string `json:"when" yaml:"when"`
+}
+
+// LoadManifest reads the manifest at the root of dir. It returns nil when
+// the template has none.
+func LoadManifest(dir string) (*Manifest, error) {
+	for _, name := range ManifestFiles {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		manifest := &Manifest{file: name}
+		if filepath.Ext(name) == ".json" {
+			err = json.Unmarshal(data, manifest)
+		} else {
+			err = yaml.Unmarshal(data, manifest)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %v", name, err)
+		}
+		if err := manifest.validate(); err != nil {
+			return nil, fmt.Errorf("invalid %s: %v", name, err)
+		}
+		return manifest, nil
+	}
+	return nil, nil
+}
+
+// validate checks the variable definitions and compiles their patterns
+func (m *Manifest) validate() error {
+	seen := map[string]bool{"Name": true}
+	for i := range m.Variables {
+		v := &m.Variables[i]
+		if v.Name == "" {
+			return fmt.Errorf("variable %d has no name", i+1)
+		}
+		if seen[v.Name] {
+			return fmt.Errorf("variable %s is defined twice or is reserved", v.Name)
+		}
+		seen[v.Name] = true
+
+		if v.Type == "" {
+			v.Type = VarString
+			if len(v.Choices) > 0 {
+				v.Type = VarEnum
+			}
+		}
+		switch v.Type {
+		case VarString, VarBool, VarInt:
+		case VarEnum:
+			if len(v.Choices) == 0 {
+				return fmt.Errorf("enum variable %s requires choices", v.Name)
+			}
+		default:
+			return fmt.Errorf("variable %s has unknown type %s", v.Name, v.Type)
+		}
+
+		if v.Pattern != "" {
+			re, err := regexp.Compile(v.Pattern)
+			if err != nil {
+				return fmt.Errorf("variable %s has an invalid pattern: %v", v.Name, err)
+			}
+			v.pattern = re
+		}
+	}
+
+	for _, rule := range m.Files {
+		if rule.Path == "" || rule.When == "" {
+			return fmt.Errorf("file rules require a path and a when condition")
+		}
+	}
+	return nil
+}
+
+// Normalize validates value against the variable and returns its
+// canonical form. An empty value leaves an optional variable unset and is
+// not validated; Values then uses the zero value of its type.
+func (v Variable) Normalize(value string) (string, error) {
+	if value == "" {
+		if v.Required {
+			return "", fmt.Errorf("%s is required", v.Name)
+		}
+		return "", nil
+	}
+
+	switch v.Type {
+	case VarBool:
+		switch strings.ToLower(strings.TrimSpace(value)) {
+		case "true", "yes", "y", "1", "on":
+			return "true", nil
+		case "false", "no", "n", "0", "off":
+			return "false", nil
+		}
+		return "", fmt.Errorf("invalid value %q for %s: expected true or false", value, v.Name)
+	case VarInt:
+		n, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return "", fmt.Errorf("invalid value %q for %s: expected an integer", value, v.Name)
+		}
+		value = strconv.Itoa(n)
+	case VarEnum:
+		for _, choice := range v.Choices {
+			if value == choice {
+				return value, nil
+			}
+		}
+		return "", fmt.Errorf("invalid value %q for %s: must be one of %s", value, v.Name, strings.Join(v.Choices, ", "))
+	}
+
+	if v.pattern != nil && !v.pattern.MatchString(value) {
+		return "", fmt.Errorf("invalid value %q for %s: must match %s", value, v.Name, v.Pattern)
+	}
+	return value, nil
+}
+
+// Check validates values supplied up front, e.g. with --var, before any
+// question is asked: every name must be an input variable and every value
+// valid. Unless interactive, required variables without a default must be
+// supplied.
+func (m *Manifest) Check(values map[string]string, interactive bool) error {
+	byName := make(map[string]Variable, len(m.Variables))
+	for _, v := range m.Variables {
+		byName[v.Name] = v
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		v, ok := byName[name]
+		switch {
+		case !ok:
+			return fmt.Errorf("unknown template variable: %s", name)
+		case v.Computed != "":
+			return fmt.Errorf("template variable %s is computed and cannot be set", name)
+		}
+		if _, err := v.Normalize(values[name]); err != nil {
+			return err
+		}
+	}
+
+	if interactive {
+		return nil
+	}
+
+	var missing []string
+	for _, v := range m.Variables {
+		if _, ok := values[v.Name]; !ok && v.Required && v.Computed == "" && v.Default == nil {
+			missing = append(missing, v.Name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing required template variables: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// Answers returns the values of the input variables in data as strings,
+// in the form Check and Values accept them
+func (m *Manifest) Answers(data map[string]interface{}) map[string]string {
+	answers := make(map[string]string)
+	for _, v := range m.Variables {
+		if value, ok := data[v.Name]; ok && v.Computed == "" {
+			answers[v.Name] = util.FormatValue(value)
+		}
+	}
+	return answers
+}
+
+// typed converts a normalized value to the Go type used in templates, so
+// bools can be tested with {{if .docker}}. Unset values become false, 0 or
+// the empty string.
+func (v Variable) typed(value string) interface{} {
+	switch v.Type {
+	case VarBool:
+		return value == "true"
+	case VarInt:
+		n, _ := strconv.Atoi(value)
+		return n
+	}
+	return value
+}
+
+// AskFunc returns the value of variable v, offering def as the default
+type AskFunc func(v Variable, def string) (string, error)
+
+// Values resolves the manifest variables for the project called name.
+// Input variables are passed to ask with their rendered default; computed
+// variables are rendered. The result is the data templates are rendered
+// with, including the project name as .Name.
+func (m *Manifest) Values(name string, ask AskFunc) (map[string]interface{}, error) {
+	data := map[string]interface{}{"Name": name}
+	for _, v := range m.Variables {
+		if v.Computed != "" {
+			value, err := render(v.Computed, data)
+			if err != nil {
+				return nil, fmt.Errorf("variable %s: %v", v.Name, err)
+			}
+			data[v.Name] = value
+			continue
+		}
+
+		def := ""
+		if v.Default != nil {
+			rendered, err := render(util.FormatValue(v.Default), data)
+			if err != nil {
+				return nil, fmt.Errorf("default of %s: %v", v.Name, err)
+			}
+			def = rendered
+		}
+
+		value, err := ask(v, def)
+		if err != nil {
+			return nil, err
+		}
+		value, err = v.Normalize(value)
+		if err != nil {
+			return nil, err
+		}
+		data[v.Name] = v.typed(value)
+	}
+	return data, nil
+}
+
+// Apply renders the template copied into dir: files whose condition does
+// not hold are removed, file contents and names are rendered with data,
+// and the manifest itself is removed. Names that render to an empty string
+// are removed too.
+func (m *Manifest) Apply(dir string, data map[string]interface{}) error {
+	excluded := &Ignore{}
+	for _, rule := range m.Files {
+		ok, err := condition(rule.When, data)
+		if err != nil {
+			return fmt.Errorf("condition for %s: %v", rule.Path, err)
+		}
+		if !ok {
+			if parsed, valid := parseIgnoreRule(rule.Path); valid {
+				excluded.rules = append(excluded.rules, parsed)
+			}
+		}
+	}
+
+	copyOnly, err := ParseIgnore(strings.NewReader(strings.Join(m.CopyOnly, "\n")))
+	if err != nil {
+		return err
+	}
+	only, err := ParseIgnore(strings.NewReader(strings.Join(m.Render, "\n")))
+	if err != nil {
+		return err
+	}
+	renders := func(path string) bool {
+		if copyOnly.Match(path, false) {
+			return false
+		}
+		return len(m.Render) == 0 || only.Match(path, false)
+	}
+
+	if m.file != "" {
+		if err := os.Remove(filepath.Join(dir, m.file)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return m.applyDir(dir, "", data, excluded, renders)
+}
+
+// applyDir renders the entries of the directory at rel below root. Names
+// are rendered after the contents of a directory, so paths stay valid
+// while walking. Only files for which renders holds have their contents
+// rendered.
+func (m *Manifest) applyDir(root, rel string, data map[string]interface{}, excluded *Ignore, renders func(path string) bool) error {
+	dir := filepath.Join(root, filepath.FromSlash(rel))
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		path := entry.Name()
+		if rel != "" {
+			path = rel + "/" + entry.Name()
+		}
+		full := filepath.Join(dir, entry.Name())
+		isDir := entry.IsDir()
+
+		if excluded.Match(path, isDir) {
+			if err := os.RemoveAll(full); err != nil {
+				return err
+			}
+			continue
+		}
+
+		switch {
+		case isDir:
+			if err := m.applyDir(root, path, data, excluded, renders); err != nil {
+				return err
+			}
+		case entry.Type().IsRegular() && renders(path):
+			if err := renderFile(full, path, data); err != nil {
+				return fmt.Errorf("%v (list files that are not templates under copyOnly, or the templates under render, in %s)", err, m.name())
+			}
+		}
+
+		if err := renderName(dir, entry.Name(), path, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// name returns the manifest's file name, for messages
+func (m *Manifest) name() string {
+	if m.file == "" {
+		return ManifestFiles[0]
+	}
+	return m.file
+}
+
+// renderFile renders the contents of the text file at full in place
+func renderFile(full, path string, data map[string]interface{}) error {
+	content, err := os.ReadFile(full)
+	if err != nil {
+		return err
+	}
+	if !bytes.Contains(content, []byte("{{")) || isBinary(content) {
+		return nil
+	}
+
+	rendered, err := render(string(content), data)
+	if err != nil {
+		return fmt.Errorf("failed to render %s: %v", path, err)
+	}
+
+	info, err := os.Stat(full)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(full, []byte(rendered), info.Mode().Perm())
+}
+
+// renderName renames the entry called name in dir to its rendered name
+func renderName(dir, name, path string, data map[string]interface{}) error {
+	if !strings.Contains(name, "{{") {
+		return nil
+	}
+
+	rendered, err := render(name, data)
+	if err != nil {
+		return fmt.Errorf("failed to render the name of %s: %v", path, err)
+	}
+	if rendered == "" {
+		return os.RemoveAll(filepath.Join(dir, name))
+	}
+	if rendered == "." || rendered == ".." || strings.ContainsAny(rendered, `/\`) {
+		return fmt.Errorf("name of %s renders to the invalid name %q", path, rendered)
+	}
+
+	target := filepath.Join(dir, rendered)
+	if _, err := os.Lstat(target); err == nil {
+		return fmt.Errorf("name of %s renders to %s, which already exists", path, rendered)
+	}
+	return os.Rename(filepath.Join(dir, name), target)
+}
+
+// condition reports whether the template expression when holds: it must
+// render to something other than "", "false" or "0"
+func condition(when string, data map[string]interface{}) (bool, error) {
+	if !strings.Contains(when, "{{") {
+		when = "{{" + when + "}}"
+	}
+	value, err := render(when, data)
+	if err != nil {
+		return false, err
+	}
+	switch strings.TrimSpace(value) {
+	case "", "false", "0":
+		return false, nil
+	}
+	return true, nil
+}
+
+// render executes text as a template with data. Unknown variables are
+// errors, so typos in templates are not silently rendered empty.
+func render(text string, data map[string]interface{}) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+
+	tmpl, err := template.New("template").Option("missingkey=error").Funcs(providers.TextFuncs()).Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// isBinary reports whether content looks like a binary file
+func isBinary(content []byte) bool {
+	if len(content) > 8000 {
+		content = content[:8000]
+	}
+	return bytes.IndexByte(content, 0) >= 0
+}
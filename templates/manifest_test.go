@@ -0,0 +1,282 @@
+package templates
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// answers returns an AskFunc answering from values, or with the default
+func answers(values map[string]string) AskFunc {
+	return func(v Variable, def string) (string, error) {
+		if value, ok := values[v.Name]; ok {
+			return value, nil
+		}
+		return def, nil
+	}
+}
+
+func TestManifest(t *testing.T) {
+	manifestJSON := `{
+  "variables": [
+    {"name": "service", "prompt": "Service name", "default": "{{kebab .Name}}", "pattern": "^[a-z-]+$"},
+    {"name": "database", "choices": ["postgres", "mysql", "none"], "default": "postgres"},
+    {"name": "docker", "type": "bool", "default": false},
+    {"name": "port", "type": "int", "default": 8080},
+    {"name": "module", "computed": "github.com/example/{{.service}}"}
+  ],
+  "files": [
+    {"path": "docker/", "when": ".docker"},
+    {"path": "db/{{.database}}.sql", "when": "ne .database \"none\""}
+  ],
+  "copyOnly": ["static/**"]
+}`
+
+	newTemplate := func(t *testing.T) string {
+		t.Helper()
+		dir := t.TempDir()
+		writeTree(t, dir, map[string]string{
+			"bootstraper.json":        manifestJSON,
+			"go.mod":                  "module {{.module}}\n",
+			"main.go":                 "// {{.service}} listens on {{.port}}{{if .docker}} in docker{{end}}\n",
+			"docker/Dockerfile":       "EXPOSE {{.port}}\n",
+			"db/{{.database}}.sql":    "-- {{.database}}\n",
+			"cmd/{{.service}}/run.go": "package main\n",
+			"static/app.js":           "const x = `{{ not rendered }}`\n",
+		})
+		return dir
+	}
+
+	t.Run("Renders contents and names with answers", func(t *testing.T) {
+		dir := newTemplate(t)
+		manifest, err := LoadManifest(dir)
+		if err != nil || manifest == nil {
+			t.Fatalf("Expected manifest, got %v (%v)", manifest, err)
+		}
+
+		values, err := manifest.Values("OrderService", answers(map[string]string{"docker": "y", "database": "mysql"}))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if err := manifest.Apply(dir, values); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		expected := map[string]string{
+			"go.mod":                   "module github.com/example/order-service\n",
+			"main.go":                  "// order-service listens on 8080 in docker\n",
+			"docker/Dockerfile":        "EXPOSE 8080\n",
+			"db/mysql.sql":             "-- mysql\n",
+			"cmd/order-service/run.go": "package main\n",
+			"static/app.js":            "const x = `{{ not rendered }}`\n",
+		}
+		for name, want := range expected {
+			data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+			if err != nil || string(data) != want {
+				t.Errorf("Expected %s to be %q, got %q (%v)", name, want, data, err)
+			}
+		}
+		if _, err := os.Stat(filepath.Join(dir, "bootstraper.json")); !os.IsNotExist(err) {
+			t.Error("Expected the manifest to be removed")
+		}
+	})
+
+	t.Run("Conditional files are removed", func(t *testing.T) {
+		dir := newTemplate(t)
+		manifest, _ := LoadManifest(dir)
+		values, err := manifest.Values("app", answers(map[string]string{"database": "none"}))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if err := manifest.Apply(dir, values); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		for _, name := range []string{"docker", "db/none.sql"} {
+			if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(name))); !os.IsNotExist(err) {
+				t.Errorf("Expected %s to be removed", name)
+			}
+		}
+	})
+
+	t.Run("Answers are validated", func(t *testing.T) {
+		dir := newTemplate(t)
+		manifest, _ := LoadManifest(dir)
+		tests := map[string]map[string]string{
+			"pattern": {"service": "Bad_Name"},
+			"choices": {"database": "oracle"},
+			"bool":    {"docker": "maybe"},
+			"int":     {"port": "http"},
+		}
+		for name, values := range tests {
+			if _, err := manifest.Values("app", answers(values)); err == nil {
+				t.Errorf("Expected %s validation error, got nil", name)
+			}
+		}
+	})
+
+	t.Run("YAML manifests", func(t *testing.T) {
+		dir := t.TempDir()
+		writeTree(t, dir, map[string]string{
+			"bootstraper.yaml": "variables:\n  - name: greeting\n    default: hello\n",
+			"README.md":        "{{.greeting}} {{.Name}}\n",
+		})
+		manifest, err := LoadManifest(dir)
+		if err != nil || manifest == nil {
+			t.Fatalf("Expected manifest, got %v (%v)", manifest, err)
+		}
+		values, _ := manifest.Values("world", answers(nil))
+		if err := manifest.Apply(dir, values); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if data, _ := os.ReadFile(filepath.Join(dir, "README.md")); string(data) != "hello world\n" {
+			t.Errorf("Expected rendered README, got %q", data)
+		}
+	})
+
+	t.Run("Unknown variables in files are errors", func(t *testing.T) {
+		dir := t.TempDir()
+		writeTree(t, dir, map[string]string{
+			"bootstraper.json": `{"variables": []}`,
+			"README.md":        "{{.missing}}\n",
+		})
+		manifest, _ := LoadManifest(dir)
+		values, _ := manifest.Values("app", answers(nil))
+		err := manifest.Apply(dir, values)
+		if err == nil || !strings.Contains(err.Error(), "README.md") || !strings.Contains(err.Error(), "copyOnly") {
+			t.Errorf("Expected error naming README.md and copyOnly, got %v", err)
+		}
+	})
+
+	t.Run("Only files listed in render are rendered", func(t *testing.T) {
+		dir := t.TempDir()
+		writeTree(t, dir, map[string]string{
+			"bootstraper.json":          `{"variables": [], "render": ["package.json"]}`,
+			"package.json":              `{"name": "{{kebab .Name}}"}` + "\n",
+			"src/App.vue":               "<p>{{ message }}</p>\n",
+			".github/workflows/ci.yml":  "key: ${{ runner.os }}\n",
+			"cmd/{{snake .Name}}/x.txt": "{{ kept }}\n",
+		})
+		manifest, _ := LoadManifest(dir)
+		values, _ := manifest.Values("My App", answers(nil))
+		if err := manifest.Apply(dir, values); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		expected := map[string]string{
+			"package.json":             `{"name": "my-app"}` + "\n",
+			"src/App.vue":              "<p>{{ message }}</p>\n",
+			".github/workflows/ci.yml": "key: ${{ runner.os }}\n",
+			"cmd/my_app/x.txt":         "{{ kept }}\n",
+		}
+		for name, want := range expected {
+			data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+			if err != nil || string(data) != want {
+				t.Errorf("Expected %s to be %q, got %q (%v)", name, want, data, err)
+			}
+		}
+	})
+
+	t.Run("Invalid manifests", func(t *testing.T) {
+		for _, content := range []string{
+			`{"variables": [{"name": "x", "type": "enum"}]}`,
+			`{"variables": [{"name": "x", "type": "float"}]}`,
+			`{"variables": [{"name": "x", "pattern": "("}]}`,
+			`{"variables": [{"name": "Name"}]}`,
+		} {
+			dir := t.TempDir()
+			writeTree(t, dir, map[string]string{"bootstraper.json": content})
+			if _, err := LoadManifest(dir); err == nil {
+				t.Errorf("Expected error for %s, got nil", content)
+			}
+		}
+	})
+
+	t.Run("Supplied values are checked up front", func(t *testing.T) {
+		manifest, err := LoadManifest(newTemplate(t))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		manifest.Variables = append(manifest.Variables, Variable{Name: "owner", Type: VarString, Required: true})
+
+		tests := []struct {
+			name        string
+			values      map[string]string
+			interactive bool
+			want        string
+		}{
+			{"Unknown variable", map[string]string{"owner": "a", "colour": "red"}, false, "unknown template variable: colour"},
+			{"Computed variable", map[string]string{"owner": "a", "module": "x"}, false, "computed"},
+			{"Invalid value", map[string]string{"owner": "a", "port": "http"}, false, "expected an integer"},
+			{"Missing required", map[string]string{}, false, "missing required template variables: owner"},
+			{"Required may be asked", map[string]string{}, true, ""},
+			{"Valid", map[string]string{"owner": "a", "docker": "yes"}, false, ""},
+		}
+		for _, tt := range tests {
+			err := manifest.Check(tt.values, tt.interactive)
+			switch {
+			case tt.want == "" && err != nil:
+				t.Errorf("%s: unexpected error: %v", tt.name, err)
+			case tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)):
+				t.Errorf("%s: expected error containing %q, got %v", tt.name, tt.want, err)
+			}
+		}
+	})
+
+	t.Run("Answers exclude computed variables", func(t *testing.T) {
+		manifest, _ := LoadManifest(newTemplate(t))
+		values, err := manifest.Values("app", answers(map[string]string{"docker": "yes"}))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		got := manifest.Answers(values)
+		want := map[string]string{"service": "app", "database": "postgres", "docker": "true", "port": "8080"}
+		if len(got) != len(want) {
+			t.Errorf("Expected %v, got %v", want, got)
+		}
+		for name, value := range want {
+			if got[name] != value {
+				t.Errorf("Expected %s=%s, got %q", name, value, got[name])
+			}
+		}
+	})
+
+	t.Run("Optional variables without a default may be left empty", func(t *testing.T) {
+		dir := t.TempDir()
+		writeTree(t, dir, map[string]string{
+			"bootstraper.json": `{"variables": [
+				{"name": "docker", "type": "bool"},
+				{"name": "port", "type": "int"},
+				{"name": "database", "choices": ["postgres", "mysql"]},
+				{"name": "owner", "pattern": "^[a-z]+$"}
+			]}`,
+			"README.md": "{{if .docker}}docker{{end}} {{.port}} {{.database}} {{.owner}}\n",
+		})
+		manifest, _ := LoadManifest(dir)
+		if err := manifest.Check(map[string]string{}, false); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		values, err := manifest.Values("app", answers(nil))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if values["docker"] != false || values["port"] != 0 || values["database"] != "" || values["owner"] != "" {
+			t.Errorf("Expected zero values, got %v", values)
+		}
+		if err := manifest.Apply(dir, values); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if data, _ := os.ReadFile(filepath.Join(dir, "README.md")); string(data) != " 0  \n" {
+			t.Errorf("Expected README rendered with zero values, got %q", data)
+		}
+	})
+
+	t.Run("Templates without a manifest", func(t *testing.T) {
+		if manifest, err := LoadManifest(t.TempDir()); manifest != nil || err != nil {
+			t.Errorf("Expected no manifest, got %v (%v)", manifest, err)
+		}
+	})
+}
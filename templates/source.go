@@ -0,0 +1,45 @@
+package templates
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/sharik709/bootstraper/util"
+)
+
+// IsLocal reports whether source names a local directory: a path or a
+// file:// URL that is not a git repository
+func IsLocal(source string) bool {
+	if IsGit(source) {
+		return false
+	}
+	if strings.HasPrefix(source, "file://") {
+		return true
+	}
+	return !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://")
+}
+
+// LocalPath returns the absolute directory named by a local source. Paths
+// may start with ~ or use environment variables.
+func LocalPath(source string) (string, error) {
+	path := source
+	if strings.HasPrefix(source, "file://") {
+		u, err := url.Parse(source)
+		if err != nil {
+			return "", fmt.Errorf("invalid template source %s: %v", source, err)
+		}
+		if u.Host != "" && u.Host != "localhost" {
+			return "", fmt.Errorf("invalid template source %s: file URLs must name a local absolute path", source)
+		}
+		path = filepath.FromSlash(u.Path)
+	} else {
+		path = util.ExpandPath(path)
+	}
+
+	if path == "" {
+		return "", fmt.Errorf("invalid template source %q", source)
+	}
+	return filepath.Abs(path)
+}
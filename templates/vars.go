@@ -0,0 +1,52 @@
+package templates
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sharik709/bootstraper/util"
+	"gopkg.in/yaml.v3"
+)
+
+// ParseVar splits a "name=value" assignment
+func ParseVar(assignment string) (string, string, error) {
+	name, value, ok := strings.Cut(assignment, "=")
+	name = strings.TrimSpace(name)
+	if !ok || name == "" {
+		return "", "", fmt.Errorf("invalid variable %q: expected name=value", assignment)
+	}
+	return name, value, nil
+}
+
+// ReadVarsFile reads variable values from a JSON or YAML object, chosen by
+// the file extension. Scalar values are converted to strings and lists are
+// joined with commas.
+func ReadVarsFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := make(map[string]interface{})
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &raw)
+	default:
+		err = json.Unmarshal(data, &raw)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid variables file %s: %v", path, err)
+	}
+
+	values := make(map[string]string, len(raw))
+	for name, value := range raw {
+		if _, nested := value.(map[string]interface{}); nested {
+			return nil, fmt.Errorf("invalid variables file %s: %s must be a value, not an object", path, name)
+		}
+		values[name] = util.FormatValue(value)
+	}
+	return values, nil
+}
@@ -0,0 +1,54 @@
+package templates
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestVars(t *testing.T) {
+	t.Run("ParseVar", func(t *testing.T) {
+		name, value, err := ParseVar("greeting=hello=world")
+		if err != nil || name != "greeting" || value != "hello=world" {
+			t.Errorf("Expected greeting=hello=world, got %s=%s (%v)", name, value, err)
+		}
+		for _, assignment := range []string{"greeting", "=value"} {
+			if _, _, err := ParseVar(assignment); err == nil {
+				t.Errorf("Expected error for %q, got nil", assignment)
+			}
+		}
+	})
+
+	t.Run("ReadVarsFile", func(t *testing.T) {
+		dir := t.TempDir()
+		files := map[string]string{
+			"vars.json": `{"service": "orders", "docker": true, "port": 8080, "tags": ["a", "b"]}`,
+			"vars.yaml": "service: orders\ndocker: true\nport: 8080\ntags: [a, b]\n",
+		}
+		for name, content := range files {
+			path := filepath.Join(dir, name)
+			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			values, err := ReadVarsFile(path)
+			if err != nil {
+				t.Fatalf("Unexpected error reading %s: %v", name, err)
+			}
+			want := map[string]string{"service": "orders", "docker": "true", "port": "8080", "tags": "a,b"}
+			for key, value := range want {
+				if values[key] != value {
+					t.Errorf("Expected %s in %s to be %q, got %q", key, name, value, values[key])
+				}
+			}
+		}
+
+		path := filepath.Join(dir, "nested.json")
+		if err := os.WriteFile(path, []byte(`{"db": {"host": "x"}}`), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ReadVarsFile(path); err == nil {
+			t.Error("Expected error for nested values, got nil")
+		}
+	})
+}
@@ -1,7 +1,10 @@
 package util
 
 import (
+	"context"
 	"os/exec"
+	"strings"
+	"time"
 )
 
 // CommandExists checks if a command exists on the system
@@ -9,3 +12,19 @@ func CommandExists(cmd string) bool {
 	_, err := exec.LookPath(cmd)
 	return err == nil
 }
+
+// CommandPath returns the resolved path of a command
+func CommandPath(cmd string) (string, error) {
+	return exec.LookPath(cmd)
+}
+
+// CommandOutput runs a command with a timeout and returns its combined,
+// trimmed output. It is meant for short informational commands such as
+// "node --version".
+func CommandOutput(timeout time.Duration, cmd string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, cmd, args...).CombinedOutput()
+	return strings.TrimSpace(string(out)), err
+}
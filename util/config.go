@@ -5,33 +5,47 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 )
 
 // Config represents the user configuration
 type Config struct {
-	Defaults   map[string]map[string]interface{} `json:"defaults"`
-	Templates  map[string]Template               `json:"templates"`
-	Telemetry  bool                              `json:"telemetry"`
-	CacheDir   string                            `json:"cacheDir"`
-	ProjectDir string                            `json:"projectDir"`
+	Defaults    map[string]map[string]interface{} `json:"defaults"`
+	Templates   map[string]Template               `json:"templates"`
+	Telemetry   bool                              `json:"telemetry"`
+	CacheDir    string                            `json:"cacheDir"`
+	ProjectDir  string                            `json:"projectDir"`
+	RegistryURL string                            `json:"registryUrl,omitempty"`
+	// VersionSources overrides the base URL of a version resolver, e.g.
+	// {"npm": "https://npm.example.com"}
+	VersionSources map[string]string `json:"versionSources,omitempty"`
+	// Aliases maps extra framework names to providers, e.g. {"svc": "go"}
+	Aliases map[string]string `json:"aliases,omitempty"`
 }
 
+// DefaultRegistryURL is the upstream provider registry
+const DefaultRegistryURL = "https://raw.githubusercontent.com/sharik709/bootstraper/main/providers/registry.json"
+
 // Template represents a custom project template
 type Template struct {
 	Source      string   `json:"source"`
 	Description string   `json:"description"`
 	Tags        []string `json:"tags,omitempty"`
+	// SHA256 pins the checksum of an archive source
+	SHA256 string `json:"sha256,omitempty"`
 }
 
 // DefaultConfig returns the default configuration
 func DefaultConfig() *Config {
 	homeDir, _ := os.UserHomeDir()
 	return &Config{
-		Defaults:   make(map[string]map[string]interface{}),
-		Templates:  make(map[string]Template),
-		Telemetry:  true,
-		CacheDir:   filepath.Join(homeDir, ".bootstraper", "cache"),
-		ProjectDir: filepath.Join(homeDir, "Projects"),
+		Defaults:    make(map[string]map[string]interface{}),
+		Templates:   make(map[string]Template),
+		Telemetry:   true,
+		CacheDir:    filepath.Join(homeDir, ".bootstraper", "cache"),
+		ProjectDir:  filepath.Join(homeDir, "Projects"),
+		RegistryURL: DefaultRegistryURL,
 	}
 }
 
@@ -52,12 +66,13 @@ func LoadConfig() (*Config, error) {
 		return DefaultConfig(), fmt.Errorf("failed to read config file: %v", err)
 	}
 
-	var config Config
-	if err := json.Unmarshal(data, &config); err != nil {
+	// Start from the defaults so keys missing from the file keep their default value
+	config := DefaultConfig()
+	if err := json.Unmarshal(data, config); err != nil {
 		return DefaultConfig(), fmt.Errorf("failed to parse config file: %v", err)
 	}
 
-	return &config, nil
+	return config, nil
 }
 
 // SaveConfig saves the configuration to the given file
@@ -95,6 +110,28 @@ func GetConfigPath() (string, error) {
 	return filepath.Join(homeDir, ".bootstraperrc"), nil
 }
 
+// ExpandPath expands environment variables and a leading "~" in path
+func ExpandPath(path string) string {
+	path = os.ExpandEnv(path)
+	if path == "~" || strings.HasPrefix(path, "~/") || strings.HasPrefix(path, "~"+string(filepath.Separator)) {
+		if homeDir, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(homeDir, path[1:])
+		}
+	}
+	return path
+}
+
+// GetAppDir returns the directory holding bootstraper's per-user data
+// (provider overlays, cache and history)
+func GetAppDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %v", err)
+	}
+
+	return filepath.Join(homeDir, ".bootstraper"), nil
+}
+
 // GetDefaultsForProvider returns the default options for a provider
 func GetDefaultsForProvider(providerName string) (map[string]interface{}, error) {
 	config, err := LoadConfig()
@@ -123,3 +160,25 @@ func SetDefaultsForProvider(providerName string, defaults map[string]interface{}
 	config.Defaults[providerName] = defaults
 	return SaveConfig(config)
 }
+
+// FormatValue converts a decoded JSON scalar, such as a provider default from
+// the config file, to its command-line string form
+func FormatValue(v interface{}) string {
+	switch value := v.(type) {
+	case string:
+		return value
+	case bool:
+		return strconv.FormatBool(value)
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case []interface{}:
+		items := make([]string, len(value))
+		for i, item := range value {
+			items[i] = FormatValue(item)
+		}
+		return strings.Join(items, ",")
+	case nil:
+		return ""
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,58 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// HistoryEntry records a single project creation in the history log
+type HistoryEntry struct {
+	Time        time.Time         `json:"time"`
+	Provider    string            `json:"provider"`
+	Project     string            `json:"project"`
+	Path        string            `json:"path"`
+	Options     map[string]string `json:"options,omitempty"`
+	Passthrough []string          `json:"passthrough,omitempty"`
+	Error       string            `json:"error,omitempty"`
+}
+
+// GetHistoryPath returns the path to the history log, one JSON entry per line
+func GetHistoryPath() (string, error) {
+	appDir, err := GetAppDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(appDir, "history.jsonl"), nil
+}
+
+// AppendHistory appends entry to the history log
+func AppendHistory(entry HistoryEntry) error {
+	historyPath, err := GetHistoryPath()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(historyPath), 0755); err != nil {
+		return fmt.Errorf("failed to create history directory: %v", err)
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal history entry: %v", err)
+	}
+
+	f, err := os.OpenFile(historyPath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open history file: %v", err)
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write history file: %v", err)
+	}
+
+	return f.Close()
+}
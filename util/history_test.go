@@ -0,0 +1,41 @@
+package util
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestAppendHistory(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	entries := []HistoryEntry{
+		{Provider: "next", Project: "app", Passthrough: []string{"--use-pnpm"}},
+		{Provider: "go", Project: "svc", Error: "exit status 1"},
+	}
+	for _, entry := range entries {
+		if err := AppendHistory(entry); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	historyPath, _ := GetHistoryPath()
+	data, err := os.ReadFile(historyPath)
+	if err != nil {
+		t.Fatalf("Failed to read history: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != len(entries) {
+		t.Fatalf("Expected %d history lines, got %d", len(entries), len(lines))
+	}
+
+	var first HistoryEntry
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil {
+		t.Fatalf("Expected JSON line, got %q: %v", lines[0], err)
+	}
+	if first.Provider != "next" || len(first.Passthrough) != 1 || first.Passthrough[0] != "--use-pnpm" {
+		t.Errorf("Expected first entry to be recorded as written, got %+v", first)
+	}
+}
@@ -0,0 +1,63 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ProjectMetadataFile is written to the root of every created project
+const ProjectMetadataFile = ".bootstraper.json"
+
+// ProjectMetadata records how a project was created
+type ProjectMetadata struct {
+	Provider string `json:"provider,omitempty"`
+	// Template and Source name the template a project was created from
+	Template string `json:"template,omitempty"`
+	Source   string `json:"source,omitempty"`
+	// Commit is the resolved commit of a git template
+	Commit string `json:"commit,omitempty"`
+	// Version is the framework version the generator was run with
+	Version string `json:"version,omitempty"`
+	// RequestedVersion is the --version given, empty for the latest version
+	RequestedVersion string            `json:"requestedVersion,omitempty"`
+	Options          map[string]string `json:"options,omitempty"`
+	CreatedAt        time.Time         `json:"createdAt"`
+	BtVersion        string            `json:"btVersion,omitempty"`
+	// Answers are the template variable values, reusable with --answers
+	Answers map[string]string `json:"answers,omitempty"`
+}
+
+// WriteProjectMetadata writes meta to the metadata file in projectDir
+func WriteProjectMetadata(projectDir string, meta ProjectMetadata) error {
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal project metadata: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(projectDir, ProjectMetadataFile), append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write project metadata: %v", err)
+	}
+	return nil
+}
+
+// ReadProjectMetadata reads the metadata file in projectDir
+func ReadProjectMetadata(projectDir string) (*ProjectMetadata, error) {
+	return ReadProjectMetadataFile(filepath.Join(projectDir, ProjectMetadataFile))
+}
+
+// ReadProjectMetadataFile reads project metadata from the file at path
+func ReadProjectMetadataFile(path string) (*ProjectMetadata, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var meta ProjectMetadata
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, fmt.Errorf("failed to parse project metadata: %v", err)
+	}
+	return &meta, nil
+}
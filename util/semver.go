@@ -0,0 +1,253 @@
+package util
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Version is a semantic version. Missing minor or patch components are zero.
+type Version struct {
+	Major      int
+	Minor      int
+	Patch      int
+	Prerelease string
+}
+
+var versionPattern = regexp.MustCompile(`^v?(\d+)(?:\.(\d+))?(?:\.(\d+))?(?:-([0-9A-Za-z.-]+))?(?:\+[0-9A-Za-z.-]+)?$`)
+
+// ParseVersion parses versions such as "1.2.3", "v18", "8.1" or "2.0.0-rc.1"
+func ParseVersion(s string) (Version, error) {
+	m := versionPattern.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return Version{}, fmt.Errorf("invalid version: %q", s)
+	}
+
+	var v Version
+	v.Major, _ = strconv.Atoi(m[1])
+	v.Minor, _ = strconv.Atoi(m[2])
+	v.Patch, _ = strconv.Atoi(m[3])
+	v.Prerelease = m[4]
+	return v, nil
+}
+
+// String returns the version in major.minor.patch[-prerelease] form
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Prerelease != "" {
+		s += "-" + v.Prerelease
+	}
+	return s
+}
+
+// Compare returns -1, 0 or 1 when v is lower than, equal to or higher than o.
+// A prerelease sorts before the corresponding release.
+func (v Version) Compare(o Version) int {
+	for _, d := range []int{v.Major - o.Major, v.Minor - o.Minor, v.Patch - o.Patch} {
+		if d < 0 {
+			return -1
+		}
+		if d > 0 {
+			return 1
+		}
+	}
+
+	switch {
+	case v.Prerelease == o.Prerelease:
+		return 0
+	case v.Prerelease == "":
+		return 1
+	case o.Prerelease == "":
+		return -1
+	case v.Prerelease < o.Prerelease:
+		return -1
+	}
+	return 1
+}
+
+// Constraint is a version range such as ">=18", "^8.1", "~1.2.0", "1.x"
+// or ">=1.0 <2.0 || >=3". Comparators separated by spaces or commas must all
+// hold; alternatives separated by "||" are OR-ed.
+type Constraint struct {
+	raw          string
+	alternatives [][]comparator
+}
+
+type comparator struct {
+	op      string
+	version Version
+}
+
+var constraintPattern = regexp.MustCompile(`^(>=|<=|!=|==|=|>|<|\^|~)?\s*v?(\d+|[xX*])(?:\.(\d+|[xX*]))?(?:\.(\d+|[xX*]))?(?:-([0-9A-Za-z.-]+))?$`)
+
+var operatorSpacePattern = regexp.MustCompile(`(>=|<=|!=|==|=|>|<|\^|~)\s+`)
+
+// ParseConstraint parses a version constraint
+func ParseConstraint(s string) (*Constraint, error) {
+	c := &Constraint{raw: strings.TrimSpace(s)}
+	if c.raw == "" || c.raw == "*" {
+		return c, nil
+	}
+
+	for _, alt := range strings.Split(c.raw, "||") {
+		// Allow ">= 18" by joining operators with their version
+		alt = operatorSpacePattern.ReplaceAllString(alt, "$1")
+		fields := strings.FieldsFunc(alt, func(r rune) bool { return r == ' ' || r == ',' })
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("invalid version constraint: %q", s)
+		}
+
+		var comparators []comparator
+		for _, field := range fields {
+			expanded, err := parseComparator(field)
+			if err != nil {
+				return nil, fmt.Errorf("invalid version constraint %q: %v", s, err)
+			}
+			comparators = append(comparators, expanded...)
+		}
+		c.alternatives = append(c.alternatives, comparators)
+	}
+
+	return c, nil
+}
+
+// parseComparator expands a single comparator into primitive ones, e.g.
+// "^1.2" becomes ">=1.2.0" and "<2.0.0"
+func parseComparator(s string) ([]comparator, error) {
+	m := constraintPattern.FindStringSubmatch(s)
+	if m == nil {
+		return nil, fmt.Errorf("cannot parse %q", s)
+	}
+
+	op := m[1]
+	parts := m[2:5]
+
+	// Count the specified numeric components; wildcards end the version
+	var nums []int
+	for _, part := range parts {
+		if part == "" || part == "x" || part == "X" || part == "*" {
+			break
+		}
+		n, _ := strconv.Atoi(part)
+		nums = append(nums, n)
+	}
+	for len(nums) < 3 {
+		nums = append(nums, -1)
+	}
+
+	specified := 0
+	for _, n := range nums {
+		if n >= 0 {
+			specified++
+		}
+	}
+
+	floor := Version{Major: max0(nums[0]), Minor: max0(nums[1]), Patch: max0(nums[2]), Prerelease: m[5]}
+	if specified == 0 {
+		// "*" or "x" matches everything
+		return nil, nil
+	}
+
+	// The first version that no longer matches a partial version
+	next := func() Version {
+		switch specified {
+		case 1:
+			return Version{Major: floor.Major + 1}
+		case 2:
+			return Version{Major: floor.Major, Minor: floor.Minor + 1}
+		}
+		return Version{Major: floor.Major, Minor: floor.Minor, Patch: floor.Patch + 1}
+	}
+
+	switch op {
+	case "", "=", "==":
+		if specified == 3 {
+			return []comparator{{"=", floor}}, nil
+		}
+		return []comparator{{">=", floor}, {"<", next()}}, nil
+	case "!=":
+		return []comparator{{"!=", floor}}, nil
+	case ">", "<=":
+		if specified < 3 {
+			// ">1.2" means ">=1.3.0", "<=1.2" means "<1.3.0"
+			if op == ">" {
+				return []comparator{{">=", next()}}, nil
+			}
+			return []comparator{{"<", next()}}, nil
+		}
+		return []comparator{{op, floor}}, nil
+	case ">=", "<":
+		return []comparator{{op, floor}}, nil
+	case "~":
+		if specified == 1 {
+			return []comparator{{">=", floor}, {"<", Version{Major: floor.Major + 1}}}, nil
+		}
+		return []comparator{{">=", floor}, {"<", Version{Major: floor.Major, Minor: floor.Minor + 1}}}, nil
+	case "^":
+		var upper Version
+		switch {
+		case floor.Major > 0 || specified == 1:
+			upper = Version{Major: floor.Major + 1}
+		case floor.Minor > 0 || specified == 2:
+			upper = Version{Minor: floor.Minor + 1}
+		default:
+			upper = Version{Patch: floor.Patch + 1}
+		}
+		return []comparator{{">=", floor}, {"<", upper}}, nil
+	}
+
+	return nil, fmt.Errorf("unknown operator %q", op)
+}
+
+func max0(n int) int {
+	if n < 0 {
+		return 0
+	}
+	return n
+}
+
+// Check reports whether v satisfies the constraint
+func (c *Constraint) Check(v Version) bool {
+	if len(c.alternatives) == 0 {
+		return true
+	}
+
+	for _, comparators := range c.alternatives {
+		ok := true
+		for _, cmp := range comparators {
+			if !cmp.check(v) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (cmp comparator) check(v Version) bool {
+	r := v.Compare(cmp.version)
+	switch cmp.op {
+	case "=":
+		return r == 0
+	case "!=":
+		return r != 0
+	case ">":
+		return r > 0
+	case ">=":
+		return r >= 0
+	case "<":
+		return r < 0
+	case "<=":
+		return r <= 0
+	}
+	return false
+}
+
+// String returns the constraint as it was written
+func (c *Constraint) String() string {
+	return c.raw
+}
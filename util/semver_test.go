@@ -0,0 +1,75 @@
+package util
+
+import "testing"
+
+func TestParseVersion(t *testing.T) {
+	cases := map[string]string{
+		"1.2.3":      "1.2.3",
+		"v18":        "18.0.0",
+		"8.1":        "8.1.0",
+		"2.0.0-rc.1": "2.0.0-rc.1",
+		"1.0.0+meta": "1.0.0",
+	}
+
+	for input, expected := range cases {
+		v, err := ParseVersion(input)
+		if err != nil {
+			t.Errorf("Unexpected error for %q: %v", input, err)
+			continue
+		}
+		if v.String() != expected {
+			t.Errorf("Expected %q to parse as %s, got %s", input, expected, v)
+		}
+	}
+
+	if _, err := ParseVersion("latest"); err == nil {
+		t.Errorf("Expected error for 'latest', got nil")
+	}
+}
+
+func TestConstraint(t *testing.T) {
+	cases := []struct {
+		constraint string
+		version    string
+		expected   bool
+	}{
+		{">=18", "20.1.0", true},
+		{">=18", "14.17.0", false},
+		{">= 8.1", "8.1.0", true},
+		{">8.1", "8.1.9", false},
+		{">8.1", "8.2.0", true},
+		{"<=1.2", "1.2.9", true},
+		{"^8.1", "8.9.0", true},
+		{"^8.1", "9.0.0", false},
+		{"^0.2.3", "0.3.0", false},
+		{"~1.2.0", "1.2.9", true},
+		{"~1.2.0", "1.3.0", false},
+		{"1.x", "1.9.9", true},
+		{"1.x", "2.0.0", false},
+		{"14", "14.2.1", true},
+		{"14.2.1", "14.2.1", true},
+		{">=1.0 <2.0 || >=3", "2.5.0", false},
+		{">=1.0, <2.0 || >=3", "3.1.0", true},
+		{"*", "0.0.1", true},
+		{">=2.0.0", "2.0.0-rc.1", false},
+	}
+
+	for _, c := range cases {
+		constraint, err := ParseConstraint(c.constraint)
+		if err != nil {
+			t.Errorf("Unexpected error for %q: %v", c.constraint, err)
+			continue
+		}
+
+		v, _ := ParseVersion(c.version)
+		if got := constraint.Check(v); got != c.expected {
+			t.Errorf("Expected %s satisfies %q to be %v, got %v", c.version, c.constraint, c.expected, got)
+		}
+	}
+
+	for _, invalid := range []string{">=", "abc", ">=1.0 ||"} {
+		if _, err := ParseConstraint(invalid); err == nil {
+			t.Errorf("Expected error for %q, got nil", invalid)
+		}
+	}
+}